@@ -54,6 +54,11 @@ type Config struct {
 	// Unless you have a rare use case, using `64` as the BufferItems value
 	// results in good performance.
 	BufferItems int64
+	// Metrics determines whether cache statistics are kept during the cache's
+	// lifetime. There *is* some overhead to keeping statistics, so you should
+	// only set this flag to true when testing or throughput performance isn't a
+	// major factor.
+	Metrics bool
 }
 
 // Cache - cache for controller
@@ -81,11 +86,17 @@ func (c *Cache) GetTTL(key interface{}) (time.Duration, bool) {
 	return c.instance.GetTTL(key)
 }
 
+// Metrics returns statistics of the cache, it is nil unless Config.Metrics is enabled.
+func (c *Cache) Metrics() *ristretto.Metrics {
+	return c.instance.Metrics
+}
+
 func NewControllerCache(conf *Config) (Cacher, error) {
 	rInstance, err := ristretto.NewCache(&ristretto.Config{
 		NumCounters: conf.NumCounters,
 		MaxCost:     conf.MaxCost,
 		BufferItems: conf.BufferItems,
+		Metrics:     conf.Metrics,
 	})
 	if err != nil {
 		return nil, err
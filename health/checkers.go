@@ -0,0 +1,95 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/ok93-01-18/go-ms-lib/controllers"
+	"github.com/ok93-01-18/go-ms-lib/log"
+	"github.com/ok93-01-18/go-ms-lib/schedule"
+	"os"
+	"strconv"
+	"time"
+)
+
+// cacheCheckTTL - TTL of the value written by the cache check
+const cacheCheckTTL = time.Minute
+
+// cacheCheckAttempts - number of round trips before the cache check fails.
+// Caches with admission policies, like ristretto, may drop values under contention or when they are full,
+// repeated sets of the same key raise its frequency, so it is admitted.
+const cacheCheckAttempts = 3
+
+// NewCacheChecker - return Checker which makes set/get round trip of the cache.
+// The check fails if the value is not stored after cacheCheckAttempts round trips or differs from the one was set.
+func NewCacheChecker(name string, cacher controllers.Cacher) Checker {
+	return NewChecker(name, func(ctx context.Context) error {
+		key := "health:" + name
+
+		for attempt := 0; attempt < cacheCheckAttempts; attempt++ {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+
+			value := strconv.FormatInt(time.Now().UnixNano(), 10)
+			if !cacher.SetWithTTL(key, value, 1, cacheCheckTTL) {
+				continue
+			}
+			cacher.Wait()
+
+			got, ok := cacher.Get(key)
+			if !ok {
+				continue
+			}
+			if got != value {
+				return errors.New("value differs from the one was set")
+			}
+
+			cacher.Del(key)
+			return nil
+		}
+
+		return fmt.Errorf("value was not stored in %d attempts", cacheCheckAttempts)
+	})
+}
+
+// NewCronChecker - return Checker which fails if operations are not scheduled or
+// any operation failed at least maxFailures times in a row, 1 is used when maxFailures is zero
+func NewCronChecker(name string, manager *schedule.CronManager, maxFailures int64) Checker {
+	if maxFailures == 0 {
+		maxFailures = 1
+	}
+
+	return NewChecker(name, func(ctx context.Context) error {
+		if !manager.IsRunning() {
+			return errors.New("scheduler is not running")
+		}
+
+		for _, stats := range manager.Stats() {
+			if stats.ConsecutiveFailures >= maxFailures {
+				return fmt.Errorf("operation %s failed %d times in a row: %v", stats.Name, stats.ConsecutiveFailures, stats.LastError)
+			}
+		}
+		return nil
+	})
+}
+
+// NewLogChecker - return Checker which checks that log files are writable
+func NewLogChecker(name string, conf *log.Config) Checker {
+	return NewChecker(name, func(ctx context.Context) error {
+		for _, t := range log.Types {
+			p := log.FilePath(conf, t)
+
+			file, err := os.OpenFile(p, os.O_CREATE|os.O_WRONLY|os.O_APPEND, os.FileMode(conf.LogMode))
+			if err != nil {
+				return err
+			}
+
+			err = file.Close()
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
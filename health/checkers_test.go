@@ -0,0 +1,90 @@
+package health_test
+
+import (
+	"context"
+	"errors"
+	"github.com/ok93-01-18/go-ms-lib/health"
+	"github.com/ok93-01-18/go-ms-lib/log"
+	"github.com/ok93-01-18/go-ms-lib/schedule"
+	"testing"
+)
+
+// croner - schedule.Croner which runs jobs on demand
+type croner struct {
+	jobs []func()
+}
+
+func (c *croner) Start() {}
+
+func (c *croner) AddFunc(_ string, f func()) error {
+	c.jobs = append(c.jobs, f)
+	return nil
+}
+
+func (c *croner) run() {
+	for _, job := range c.jobs {
+		job()
+	}
+}
+
+// nopLogger - log.Logger discarding errors of operations
+type nopLogger struct {
+	log.Logger
+}
+
+func (nopLogger) Errorf(log.TypeEnum, string, ...interface{}) {}
+
+type task func() error
+
+func (t task) Do() error {
+	return t()
+}
+
+func TestCronChecker_Empty(t *testing.T) {
+	manager := schedule.NewCronManager(nopLogger{}, &croner{}, &[]schedule.Operation{})
+	checker := health.NewCronChecker("cron", manager, 0)
+
+	if err := checker.Check(context.Background()); err == nil {
+		t.Error("Check() before Init returned no error")
+	}
+
+	// manager without operations is ready once initialized
+	if err := manager.Init(); err != nil {
+		t.Fatalf("Init() = %v", err)
+	}
+	if err := checker.Check(context.Background()); err != nil {
+		t.Errorf("Check() of manager without operations = %v", err)
+	}
+}
+
+func TestCronChecker_Failures(t *testing.T) {
+	cron := &croner{}
+	var err error
+	manager := schedule.NewCronManager(nopLogger{}, cron, &[]schedule.Operation{{
+		Name:     "sync",
+		Interval: "@every 1s",
+		Task: task(func() error {
+			return err
+		}),
+	}})
+	if err := manager.Init(); err != nil {
+		t.Fatalf("Init() = %v", err)
+	}
+	checker := health.NewCronChecker("cron", manager, 2)
+
+	err = errors.New("failed")
+	cron.run()
+	if err := checker.Check(context.Background()); err != nil {
+		t.Errorf("Check() after one failure = %v", err)
+	}
+	cron.run()
+	if err := checker.Check(context.Background()); err == nil {
+		t.Error("Check() after two failures in a row returned no error")
+	}
+
+	err = nil
+	cron.run()
+	if err := checker.Check(context.Background()); err != nil {
+		t.Errorf("Check() after success = %v", err)
+	}
+}
@@ -0,0 +1,233 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"github.com/ok93-01-18/go-ms-lib/servers/http"
+	"sync"
+	"time"
+)
+
+// Defaults used for zero values of Config
+const (
+	DefaultTimeout       = 5 * time.Second
+	DefaultCacheDuration = time.Second
+	DefaultLivenessPath  = "/livez"
+	DefaultReadinessPath = "/readyz"
+)
+
+// Statuses of checks and reports
+const (
+	StatusOK   = "ok"
+	StatusFail = "fail"
+)
+
+// Checker - health check of the component
+type Checker interface {
+	// Name returns name of the component in the report.
+	Name() string
+
+	// Check returns error if the component is unhealthy.
+	Check(context.Context) error
+}
+
+type checkerFunc struct {
+	name  string
+	check func(context.Context) error
+}
+
+func (c *checkerFunc) Name() string {
+	return c.name
+}
+
+func (c *checkerFunc) Check(ctx context.Context) error {
+	return c.check(ctx)
+}
+
+// NewChecker - return Checker which calls the function
+func NewChecker(name string, check func(context.Context) error) Checker {
+	return &checkerFunc{name: name, check: check}
+}
+
+// CheckResult - result of the component check
+type CheckResult struct {
+	Status   string `json:"status"`
+	Error    string `json:"error,omitempty"`
+	Duration string `json:"duration"`
+}
+
+// Report - aggregated result of checks
+type Report struct {
+	Status    string                 `json:"status"`
+	CheckedAt time.Time              `json:"checked_at"`
+	Checks    map[string]CheckResult `json:"checks"`
+}
+
+type Config struct {
+	// Liveness - checks of the liveness probe, the process is considered alive when empty.
+	Liveness []Checker
+
+	// Readiness - checks of the readiness probe.
+	Readiness []Checker
+
+	// Timeout of each check, DefaultTimeout is used when zero.
+	Timeout time.Duration
+
+	// CacheDuration - reports are reused for the duration, so frequent probes don't overload components.
+	// DefaultCacheDuration is used when zero, negative value disables caching.
+	CacheDuration time.Duration
+
+	// LivenessPath - route of the liveness probe, DefaultLivenessPath is used when empty.
+	LivenessPath string
+
+	// ReadinessPath - route of the readiness probe, DefaultReadinessPath is used when empty.
+	ReadinessPath string
+}
+
+// probe - set of checks with the cached report
+type probe struct {
+	sync.Mutex
+	checkers []Checker
+	report   *Report
+}
+
+type Health struct {
+	conf      *Config
+	liveness  *probe
+	readiness *probe
+}
+
+// Liveness returns report of liveness checks.
+func (h *Health) Liveness(ctx context.Context) *Report {
+	return h.check(ctx, h.liveness)
+}
+
+// Readiness returns report of readiness checks.
+func (h *Health) Readiness(ctx context.Context) *Report {
+	return h.check(ctx, h.readiness)
+}
+
+// Register registers liveness and readiness routes on the router.
+// They respond with the report as JSON and StatusServiceUnavailable when any check fails.
+// Checks are run with the request context, so they are canceled when the client disconnects.
+func (h *Health) Register(router http.Router) {
+	router.Get(h.conf.LivenessPath, h.handler(h.Liveness))
+	router.Get(h.conf.ReadinessPath, h.handler(h.Readiness))
+}
+
+func (h *Health) handler(probe func(context.Context) *Report) http.Handler {
+	return func(ctx http.Context) error {
+		report := probe(ctx.Context())
+
+		status := http.StatusOK
+		if report.Status != StatusOK {
+			status = http.StatusServiceUnavailable
+		}
+
+		ctx.Set("Cache-Control", "no-store")
+		return ctx.Status(status).JSON(report)
+	}
+}
+
+// check runs checks of the probe concurrently or returns the cached report.
+func (h *Health) check(ctx context.Context, p *probe) *Report {
+	p.Lock()
+	defer p.Unlock()
+
+	if p.report != nil && time.Since(p.report.CheckedAt) < h.conf.CacheDuration {
+		return p.report
+	}
+
+	report := &Report{
+		Status:    StatusOK,
+		CheckedAt: time.Now(),
+		Checks:    make(map[string]CheckResult, len(p.checkers)),
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, checker := range p.checkers {
+		wg.Add(1)
+		go func(checker Checker) {
+			defer wg.Done()
+
+			result := h.run(ctx, checker)
+
+			mu.Lock()
+			report.Checks[checker.Name()] = result
+			if result.Status != StatusOK {
+				report.Status = StatusFail
+			}
+			mu.Unlock()
+		}(checker)
+	}
+	wg.Wait()
+
+	// checks canceled with the request fail regardless of components, so their report is not reused
+	if ctx.Err() == nil {
+		p.report = report
+	}
+	return report
+}
+
+// run executes the check with the timeout.
+func (h *Health) run(ctx context.Context, checker Checker) CheckResult {
+	ctx, cancel := context.WithTimeout(ctx, h.conf.Timeout)
+	defer cancel()
+
+	start := time.Now()
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- checker.Check(ctx)
+	}()
+
+	var err error
+	select {
+	case err = <-errCh:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	result := CheckResult{
+		Status:   StatusOK,
+		Duration: time.Since(start).String(),
+	}
+	if err != nil {
+		result.Status = StatusFail
+		result.Error = err.Error()
+	}
+	return result
+}
+
+// NewHealth - return Health running checks of the config, error is returned if names of probe checks are not unique
+func NewHealth(conf *Config) (*Health, error) {
+	for _, checkers := range [][]Checker{conf.Liveness, conf.Readiness} {
+		names := make(map[string]struct{}, len(checkers))
+		for _, checker := range checkers {
+			if _, ok := names[checker.Name()]; ok {
+				return nil, fmt.Errorf("health: duplicate checker name %q", checker.Name())
+			}
+			names[checker.Name()] = struct{}{}
+		}
+	}
+
+	c := *conf
+	if c.Timeout == 0 {
+		c.Timeout = DefaultTimeout
+	}
+	if c.CacheDuration == 0 {
+		c.CacheDuration = DefaultCacheDuration
+	}
+	if c.LivenessPath == "" {
+		c.LivenessPath = DefaultLivenessPath
+	}
+	if c.ReadinessPath == "" {
+		c.ReadinessPath = DefaultReadinessPath
+	}
+
+	return &Health{
+		conf:      &c,
+		liveness:  &probe{checkers: c.Liveness},
+		readiness: &probe{checkers: c.Readiness},
+	}, nil
+}
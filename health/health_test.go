@@ -0,0 +1,144 @@
+package health_test
+
+import (
+	"context"
+	"errors"
+	"github.com/ok93-01-18/go-ms-lib/health"
+	"github.com/ok93-01-18/go-ms-lib/servers/http"
+	"github.com/ok93-01-18/go-ms-lib/servers/http/httpassert"
+	nethttp "net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newHealth(t *testing.T, conf *health.Config) *health.Health {
+	t.Helper()
+
+	h, err := health.NewHealth(conf)
+	if err != nil {
+		t.Fatalf("NewHealth() = %v", err)
+	}
+	return h
+}
+
+func ok(name string) health.Checker {
+	return health.NewChecker(name, func(ctx context.Context) error {
+		return nil
+	})
+}
+
+func failing(name string) health.Checker {
+	return health.NewChecker(name, func(ctx context.Context) error {
+		return errors.New("unavailable")
+	})
+}
+
+func TestHealth_Aggregation(t *testing.T) {
+	h := newHealth(t, &health.Config{
+		Liveness:  []health.Checker{ok("app")},
+		Readiness: []health.Checker{ok("cache"), failing("db")},
+	})
+
+	liveness := h.Liveness(context.Background())
+	if liveness.Status != health.StatusOK || len(liveness.Checks) != 1 {
+		t.Errorf("Liveness() = %+v, want ok report of one check", liveness)
+	}
+
+	readiness := h.Readiness(context.Background())
+	if readiness.Status != health.StatusFail {
+		t.Errorf("Readiness() status = %s, want %s", readiness.Status, health.StatusFail)
+	}
+	if result := readiness.Checks["cache"]; result.Status != health.StatusOK || result.Error != "" {
+		t.Errorf("cache check = %+v, want ok", result)
+	}
+	if result := readiness.Checks["db"]; result.Status != health.StatusFail || result.Error != "unavailable" {
+		t.Errorf("db check = %+v, want failed with unavailable", result)
+	}
+}
+
+func TestHealth_Timeout(t *testing.T) {
+	h := newHealth(t, &health.Config{
+		Readiness: []health.Checker{health.NewChecker("slow", func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		})},
+		Timeout: 10 * time.Millisecond,
+	})
+
+	report := h.Readiness(context.Background())
+	if result := report.Checks["slow"]; result.Status != health.StatusFail || result.Error != context.DeadlineExceeded.Error() {
+		t.Errorf("slow check = %+v, want failed by timeout", result)
+	}
+}
+
+func TestHealth_Cache(t *testing.T) {
+	for _, test := range []struct {
+		cacheDuration time.Duration
+		wantChecks    int32
+	}{
+		{cacheDuration: 0, wantChecks: 1},
+		{cacheDuration: -1, wantChecks: 2},
+	} {
+		var checks int32
+		h := newHealth(t, &health.Config{
+			Readiness: []health.Checker{health.NewChecker("counter", func(ctx context.Context) error {
+				atomic.AddInt32(&checks, 1)
+				return nil
+			})},
+			CacheDuration: test.cacheDuration,
+		})
+
+		first := h.Readiness(context.Background())
+		second := h.Readiness(context.Background())
+		if checks != test.wantChecks {
+			t.Errorf("CacheDuration %v: checks run %d times, want %d", test.cacheDuration, checks, test.wantChecks)
+		}
+		if cached := first == second; cached != (test.wantChecks == 1) {
+			t.Errorf("CacheDuration %v: report reused = %t", test.cacheDuration, cached)
+		}
+	}
+}
+
+func TestHealth_CanceledNotCached(t *testing.T) {
+	h := newHealth(t, &health.Config{
+		Readiness: []health.Checker{health.NewChecker("context", func(ctx context.Context) error {
+			return ctx.Err()
+		})},
+		CacheDuration: time.Hour,
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if report := h.Readiness(ctx); report.Status != health.StatusFail {
+		t.Errorf("Readiness() with canceled context = %s, want %s", report.Status, health.StatusFail)
+	}
+	// failed report of the canceled request is not reused
+	if report := h.Readiness(context.Background()); report.Status != health.StatusOK {
+		t.Errorf("Readiness() after canceled check = %s, want %s", report.Status, health.StatusOK)
+	}
+}
+
+func TestHealth_Register(t *testing.T) {
+	h := newHealth(t, &health.Config{
+		Readiness: []health.Checker{failing("db")},
+	})
+	server := http.NewStdServer(nil)
+	h.Register(server)
+
+	httpassert.Do(t, server, httpassert.NewRequest(nethttp.MethodGet, health.DefaultLivenessPath, nil)).
+		Status(http.StatusOK).
+		Header("Cache-Control", "no-store").
+		BodyContains(`"status":"ok"`)
+
+	httpassert.Do(t, server, httpassert.NewRequest(nethttp.MethodGet, health.DefaultReadinessPath, nil)).
+		Status(http.StatusServiceUnavailable).
+		BodyContains(`"db":{"status":"fail","error":"unavailable"`)
+}
+
+func TestNewHealth_DuplicateName(t *testing.T) {
+	_, err := health.NewHealth(&health.Config{Readiness: []health.Checker{ok("db"), failing("db")}})
+	if err == nil {
+		t.Error("NewHealth() with duplicate names returned no error")
+	}
+}
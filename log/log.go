@@ -1,5 +1,10 @@
 package log
 
+import (
+	"fmt"
+	"path/filepath"
+)
+
 type TypeEnum string
 
 const (
@@ -8,6 +13,9 @@ const (
 	TypeApp           = "app"
 )
 
+// Types - log types, each of them is written to its own file
+var Types = []TypeEnum{TypeGet, TypeApp, TypePost}
+
 type Level string
 
 const (
@@ -36,6 +44,64 @@ type Logger interface {
 	Close()
 }
 
+// FieldLogger - Logger which can add structured fields to records
+type FieldLogger interface {
+	Logger
+
+	// WithField returns the logger which adds the field to each record.
+	// The returned logger shares log files with the parent one, so only the parent one has to be closed.
+	WithField(key string, value interface{}) Logger
+}
+
+// WithField returns the logger which adds the field to each record.
+// FieldLogger adds it as structured field, other loggers get it appended to messages as key=value.
+func WithField(logger Logger, key string, value interface{}) Logger {
+	if fieldLogger, ok := logger.(FieldLogger); ok {
+		return fieldLogger.WithField(key, value)
+	}
+	return &messageFieldLogger{Logger: logger, fields: fmt.Sprintf(" %s=%v", key, value)}
+}
+
+// messageFieldLogger - FieldLogger appending fields to messages of the wrapped logger
+type messageFieldLogger struct {
+	Logger
+	fields string
+}
+
+func (m *messageFieldLogger) Errorf(t TypeEnum, format string, args ...interface{}) {
+	m.Logger.Errorf(t, "%s", m.message(format, args))
+}
+
+func (m *messageFieldLogger) Warnf(t TypeEnum, format string, args ...interface{}) {
+	m.Logger.Warnf(t, "%s", m.message(format, args))
+}
+
+func (m *messageFieldLogger) Debugf(t TypeEnum, format string, args ...interface{}) {
+	m.Logger.Debugf(t, "%s", m.message(format, args))
+}
+
+func (m *messageFieldLogger) Infof(t TypeEnum, format string, args ...interface{}) {
+	m.Logger.Infof(t, "%s", m.message(format, args))
+}
+
+func (m *messageFieldLogger) Fatalf(t TypeEnum, format string, args ...interface{}) {
+	m.Logger.Fatalf(t, "%s", m.message(format, args))
+}
+
+func (m *messageFieldLogger) WithField(key string, value interface{}) Logger {
+	return &messageFieldLogger{Logger: m.Logger, fields: m.fields + fmt.Sprintf(" %s=%v", key, value)}
+}
+
+// Close does nothing, the wrapped logger has to be closed instead.
+func (m *messageFieldLogger) Close() {}
+
+func (m *messageFieldLogger) message(format string, args []interface{}) string {
+	if len(args) == 0 {
+		return format + m.fields
+	}
+	return fmt.Sprintf(format, args...) + m.fields
+}
+
 func GetLogTypeByRequestType(rType string) TypeEnum {
 	lType := TypeGet
 	if rType == "POST" {
@@ -44,6 +110,11 @@ func GetLogTypeByRequestType(rType string) TypeEnum {
 	return lType
 }
 
+// FilePath returns path of the log file of the type.
+func FilePath(conf *Config, t TypeEnum) string {
+	return filepath.Clean(conf.LogDir + "/" + string(t) + ".log")
+}
+
 func NewLog(conf *Config) (Logger, error) {
 	log := Zerolog{conf: conf}
 	err := log.init()
@@ -4,7 +4,6 @@ import (
 	"fmt"
 	"github.com/rs/zerolog"
 	"os"
-	"path/filepath"
 )
 
 type Type struct {
@@ -43,9 +42,9 @@ func (z *Zerolog) init() error {
 	zerolog.TimeFieldFormat = "02/Jan/2006:15:04:05"
 
 	z.loggers = make(map[TypeEnum]Type)
-	for _, t := range []TypeEnum{TypeGet, TypeApp, TypePost} {
+	for _, t := range Types {
 
-		p := filepath.Clean(z.conf.LogDir + "/" + string(t) + ".log")
+		p := FilePath(z.conf, t)
 		file, err := os.OpenFile(p, os.O_CREATE|os.O_WRONLY|os.O_APPEND, os.FileMode(z.conf.LogMode))
 		if err != nil {
 			z.Close()
@@ -93,6 +92,20 @@ func (z *Zerolog) Fatalf(t TypeEnum, format string, args ...interface{}) {
 	z.write(logger.Fatal(), format, args...)
 }
 
+func (z *Zerolog) WithField(key string, value interface{}) Logger {
+	loggers := make(map[TypeEnum]Type, len(z.loggers))
+	for t, logger := range z.loggers {
+		loggers[t] = Type{
+			logger: logger.logger.With().Interface(key, value).Logger(),
+		}
+	}
+
+	return &Zerolog{
+		conf:    z.conf,
+		loggers: loggers,
+	}
+}
+
 func (z *Zerolog) write(event *zerolog.Event, format string, args ...interface{}) {
 	if len(args) == 0 {
 		event.Msg(format)
@@ -103,6 +116,11 @@ func (z *Zerolog) write(event *zerolog.Event, format string, args ...interface{}
 
 func (z *Zerolog) Close() {
 	for _, logger := range z.loggers {
+		// loggers created by WithField don't own files
+		if logger.file == nil {
+			continue
+		}
+
 		err := logger.file.Close()
 		if err != nil {
 			fmt.Printf("error via file %s close %s\n", logger.file.Name(), err)
@@ -0,0 +1,51 @@
+package metrics
+
+import (
+	"github.com/dgraph-io/ristretto"
+	"github.com/ok93-01-18/go-ms-lib/controllers"
+)
+
+// cacheMetrics - cache which keeps statistics, e.g. controllers.Cache
+type cacheMetrics interface {
+	Metrics() *ristretto.Metrics
+}
+
+type cacheCollector struct {
+	name   string
+	cacher controllers.Cacher
+}
+
+func (c *cacheCollector) Collect() []Family {
+	cache, ok := c.cacher.(cacheMetrics)
+	if !ok || cache.Metrics() == nil {
+		return nil
+	}
+
+	m := cache.Metrics()
+	labels := []Label{{Name: "cache", Value: c.name}}
+	gauge := func(name, help string, value float64) Family {
+		return Family{
+			Name:    name,
+			Help:    help,
+			Type:    TypeGauge,
+			Samples: []Sample{{Name: name, Labels: labels, Value: value}},
+		}
+	}
+
+	return []Family{
+		gauge("cache_hits", "Number of cache hits.", float64(m.Hits())),
+		gauge("cache_misses", "Number of cache misses.", float64(m.Misses())),
+		gauge("cache_hit_ratio", "Ratio of cache hits to all gets.", m.Ratio()),
+		gauge("cache_keys_added", "Number of keys added to the cache.", float64(m.KeysAdded())),
+		gauge("cache_keys_evicted", "Number of keys evicted from the cache.", float64(m.KeysEvicted())),
+		gauge("cache_cost_evicted", "Sum of costs of keys evicted from the cache.", float64(m.CostEvicted())),
+		gauge("cache_sets_dropped", "Number of sets dropped by the cache.", float64(m.SetsDropped())),
+	}
+}
+
+// NewCacheCollector - return Collector of cache statistics labeled with the cache name.
+// Statistics are collected only if the cache keeps them, i.e. controllers.Cache created with Config.Metrics.
+// They are exposed as gauges, because ristretto statistics can be cleared.
+func NewCacheCollector(name string, cacher controllers.Cacher) Collector {
+	return &cacheCollector{name: name, cacher: cacher}
+}
@@ -0,0 +1,56 @@
+package metrics
+
+import (
+	"github.com/ok93-01-18/go-ms-lib/schedule"
+)
+
+type cronCollector struct {
+	name    string
+	manager *schedule.CronManager
+}
+
+func (c *cronCollector) Collect() []Family {
+	running := 0.0
+	if c.manager.IsRunning() {
+		running = 1
+	}
+
+	runs := Family{Name: "cron_job_runs_total", Help: "Total number of job runs.", Type: TypeCounter}
+	failures := Family{Name: "cron_job_failures_total", Help: "Total number of failed job runs.", Type: TypeCounter}
+	consecutive := Family{Name: "cron_job_consecutive_failures", Help: "Number of job failures in a row.", Type: TypeGauge}
+	duration := Family{Name: "cron_job_duration_seconds", Help: "Duration of job runs in seconds.", Type: TypeSummary}
+	lastDuration := Family{Name: "cron_job_last_duration_seconds", Help: "Duration of the last job run in seconds.", Type: TypeGauge}
+	lastRun := Family{Name: "cron_job_last_run_timestamp_seconds", Help: "Start time of the last job run.", Type: TypeGauge}
+
+	for _, stats := range c.manager.Stats() {
+		labels := []Label{{Name: "cron", Value: c.name}, {Name: "job", Value: stats.Name}}
+
+		runs.Samples = append(runs.Samples, Sample{Name: runs.Name, Labels: labels, Value: float64(stats.Runs)})
+		failures.Samples = append(failures.Samples, Sample{Name: failures.Name, Labels: labels, Value: float64(stats.Failures)})
+		consecutive.Samples = append(consecutive.Samples,
+			Sample{Name: consecutive.Name, Labels: labels, Value: float64(stats.ConsecutiveFailures)})
+		duration.Samples = append(duration.Samples,
+			Sample{Name: duration.Name + "_sum", Labels: labels, Value: stats.TotalDuration.Seconds()},
+			Sample{Name: duration.Name + "_count", Labels: labels, Value: float64(stats.Runs)})
+		lastDuration.Samples = append(lastDuration.Samples,
+			Sample{Name: lastDuration.Name, Labels: labels, Value: stats.LastDuration.Seconds()})
+		lastRun.Samples = append(lastRun.Samples,
+			Sample{Name: lastRun.Name, Labels: labels, Value: float64(stats.LastRun.UnixNano()) / 1e9})
+	}
+
+	return []Family{
+		{
+			Name:    "cron_running",
+			Help:    "Whether jobs are scheduled.",
+			Type:    TypeGauge,
+			Samples: []Sample{{Name: "cron_running", Labels: []Label{{Name: "cron", Value: c.name}}, Value: running}},
+		},
+		runs, failures, consecutive, duration, lastDuration, lastRun,
+	}
+}
+
+// NewCronCollector - return Collector of statistics of the manager operations labeled with the operation name.
+// All samples are labeled with the name as "cron", so collectors of several managers can share the registry.
+func NewCronCollector(name string, manager *schedule.CronManager) Collector {
+	return &cronCollector{name: name, manager: manager}
+}
@@ -0,0 +1,107 @@
+package metrics
+
+import (
+	"github.com/ok93-01-18/go-ms-lib/servers/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+type HTTPConfig struct {
+	// Registry receives metrics of the middleware.
+	Registry *Registry
+
+	// Namespace - prefix of metric names, e.g. "myservice" gives "myservice_http_requests_total".
+	Namespace string
+
+	// Buckets of the request duration histogram, DefaultBuckets are used when empty.
+	Buckets []float64
+
+	// Skip - requests are not measured if it returns true, e.g. requests of the metrics route itself.
+	Skip func(http.Context) bool
+
+	// Router - router of measured routes, e.g. the server which uses the middleware.
+	// The route of the request is unknown to middleware until the handler is reached,
+	// so in-flight requests are labeled by its route matching the request path.
+	// Routes are read on the first request, in-flight requests are labeled by empty route when nil or none matches.
+	Router http.Router
+}
+
+// NewHTTPMiddleware - return middleware which measures requests.
+// Requests are partitioned by the route path instead of the request path, so the number of series is bounded.
+//
+// Exposed metrics:
+//
+//	http_requests_total{method,route,status} - counter of handled requests
+//	http_request_duration_seconds{method,route,status} - histogram of request durations
+//	http_requests_in_flight{method,route} - gauge of requests being handled, the route is matched by HTTPConfig.Router
+//
+// Requests which panic are measured with StatusInternalServerError before the panic is propagated.
+func NewHTTPMiddleware(conf *HTTPConfig) http.Handler {
+	prefix := ""
+	if conf.Namespace != "" {
+		prefix = conf.Namespace + "_"
+	}
+
+	requests := NewCounterVec(prefix+"http_requests_total",
+		"Total number of handled HTTP requests.", "method", "route", "status")
+	duration := NewHistogramVec(prefix+"http_request_duration_seconds",
+		"Duration of HTTP requests in seconds.", conf.Buckets, "method", "route", "status")
+	inFlight := NewGaugeVec(prefix+"http_requests_in_flight",
+		"Number of HTTP requests being handled.", "method", "route")
+
+	conf.Registry.Add(requests, duration, inFlight)
+
+	var routesOnce sync.Once
+	var routes []http.Route
+	matchRoute := func(method, path string) string {
+		if conf.Router == nil {
+			return ""
+		}
+		routesOnce.Do(func() {
+			routes = conf.Router.Routes()
+		})
+		for _, route := range routes {
+			if route.Match(method, path) {
+				return route.Path
+			}
+		}
+		return ""
+	}
+
+	return func(ctx http.Context) (err error) {
+		if conf.Skip != nil && conf.Skip(ctx) {
+			return ctx.Next()
+		}
+
+		method := ctx.Method()
+		inFlightRoute := matchRoute(method, ctx.Request().Path())
+		inFlight.Inc(method, inFlightRoute)
+		start := time.Now()
+
+		defer func() {
+			elapsed := time.Since(start)
+			inFlight.Dec(method, inFlightRoute)
+
+			r := recover()
+			status := ctx.Response().StatusCode()
+			switch {
+			case r != nil:
+				status = http.StatusInternalServerError
+			case err != nil:
+				status = http.ErrorStatusCode(err)
+			}
+			route := ctx.Route().Path
+			statusText := strconv.Itoa(status)
+
+			requests.Inc(method, route, statusText)
+			duration.Observe(elapsed.Seconds(), method, route, statusText)
+
+			if r != nil {
+				panic(r)
+			}
+		}()
+
+		return ctx.Next()
+	}
+}
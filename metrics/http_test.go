@@ -0,0 +1,75 @@
+package metrics_test
+
+import (
+	"bytes"
+	"github.com/ok93-01-18/go-ms-lib/metrics"
+	"github.com/ok93-01-18/go-ms-lib/servers/http"
+	"github.com/ok93-01-18/go-ms-lib/servers/http/httpassert"
+	nethttp "net/http"
+	"strings"
+	"testing"
+)
+
+func scrape(t *testing.T, registry *metrics.Registry) string {
+	t.Helper()
+
+	var buf bytes.Buffer
+	if err := registry.WriteText(&buf); err != nil {
+		t.Fatalf("WriteText() = %v", err)
+	}
+	return buf.String()
+}
+
+func contains(t *testing.T, text string, lines ...string) {
+	t.Helper()
+
+	for _, line := range lines {
+		if !strings.Contains(text, line+"\n") {
+			t.Errorf("metrics don't contain %q:\n%s", line, text)
+		}
+	}
+}
+
+func TestHTTPMiddleware(t *testing.T) {
+	registry := metrics.NewRegistry()
+	server := http.NewStdServer(nil)
+	server.Use(metrics.NewHTTPMiddleware(&metrics.HTTPConfig{Registry: registry, Namespace: "api", Router: server}))
+
+	entered, release := make(chan struct{}), make(chan struct{})
+	server.Get("/users/:id", func(ctx http.Context) error {
+		close(entered)
+		<-release
+		return ctx.SendStatus(http.StatusOK)
+	})
+	server.Get("/missing", func(ctx http.Context) error {
+		return http.NotFound()
+	})
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		httpassert.Do(t, server, httpassert.NewRequest(nethttp.MethodGet, "/users/1", nil)).Status(http.StatusOK)
+	}()
+
+	// in-flight request is labeled by the route like other metrics
+	<-entered
+	contains(t, scrape(t, registry),
+		"# TYPE api_http_requests_in_flight gauge",
+		`api_http_requests_in_flight{method="GET",route="/users/:id"} 1`,
+	)
+	close(release)
+	<-done
+
+	httpassert.Do(t, server, httpassert.NewRequest(nethttp.MethodGet, "/missing", nil)).Status(http.StatusNotFound)
+
+	contains(t, scrape(t, registry),
+		"# HELP api_http_requests_total Total number of handled HTTP requests.",
+		"# TYPE api_http_requests_total counter",
+		`api_http_requests_total{method="GET",route="/users/:id",status="200"} 1`,
+		`api_http_requests_total{method="GET",route="/missing",status="404"} 1`,
+		"# TYPE api_http_request_duration_seconds histogram",
+		`api_http_request_duration_seconds_bucket{method="GET",route="/users/:id",status="200",le="+Inf"} 1`,
+		`api_http_request_duration_seconds_count{method="GET",route="/users/:id",status="200"} 1`,
+		`api_http_requests_in_flight{method="GET",route="/users/:id"} 0`,
+	)
+}
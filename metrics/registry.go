@@ -0,0 +1,178 @@
+package metrics
+
+import (
+	"bufio"
+	"bytes"
+	"github.com/ok93-01-18/go-ms-lib/servers/http"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultPath - route of the metrics endpoint
+const DefaultPath = "/metrics"
+
+// ContentType - content type of Prometheus text exposition format
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// Types of metric families
+const (
+	TypeCounter   = "counter"
+	TypeGauge     = "gauge"
+	TypeHistogram = "histogram"
+	TypeSummary   = "summary"
+)
+
+// Label - name and value of the sample label
+type Label struct {
+	Name  string
+	Value string
+}
+
+// Sample - single value of the metric family
+type Sample struct {
+	// Name of the sample, it is the family name with optional suffix, e.g. "_bucket" of histograms.
+	Name   string
+	Labels []Label
+	Value  float64
+}
+
+// Family - metric family with its samples
+type Family struct {
+	Name    string
+	Help    string
+	Type    string
+	Samples []Sample
+}
+
+// Collector - source of metric families
+type Collector interface {
+	// Collect returns current state of metric families.
+	Collect() []Family
+}
+
+// Registry - set of collectors exposed together
+type Registry struct {
+	sync.RWMutex
+	collectors []Collector
+}
+
+// Add adds collectors to the registry.
+func (r *Registry) Add(collectors ...Collector) {
+	r.Lock()
+	defer r.Unlock()
+
+	r.collectors = append(r.collectors, collectors...)
+}
+
+// Gather collects metric families sorted by name,
+// samples of families with the same name are merged.
+func (r *Registry) Gather() []Family {
+	r.RLock()
+	defer r.RUnlock()
+
+	byName := make(map[string]*Family)
+	var names []string
+	for _, collector := range r.collectors {
+		for _, family := range collector.Collect() {
+			existing, ok := byName[family.Name]
+			if !ok {
+				family := family // copy
+				byName[family.Name] = &family
+				names = append(names, family.Name)
+				continue
+			}
+			existing.Samples = append(existing.Samples, family.Samples...)
+		}
+	}
+
+	sort.Strings(names)
+	families := make([]Family, 0, len(names))
+	for _, name := range names {
+		families = append(families, *byName[name])
+	}
+	return families
+}
+
+// WriteText writes metric families in Prometheus text exposition format.
+func (r *Registry) WriteText(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	for _, family := range r.Gather() {
+		if family.Help != "" {
+			bw.WriteString("# HELP " + family.Name + " " + escapeHelp(family.Help) + "\n")
+		}
+		if family.Type != "" {
+			bw.WriteString("# TYPE " + family.Name + " " + family.Type + "\n")
+		}
+
+		for _, sample := range family.Samples {
+			bw.WriteString(sample.Name)
+			if len(sample.Labels) > 0 {
+				bw.WriteByte('{')
+				for i, label := range sample.Labels {
+					if i > 0 {
+						bw.WriteByte(',')
+					}
+					bw.WriteString(label.Name + `="` + escapeLabelValue(label.Value) + `"`)
+				}
+				bw.WriteByte('}')
+			}
+			bw.WriteString(" " + formatFloat(sample.Value) + "\n")
+		}
+	}
+	return bw.Flush()
+}
+
+// Handler returns handler which responds with metrics in Prometheus text exposition format.
+func (r *Registry) Handler() http.Handler {
+	return func(ctx http.Context) error {
+		var buf bytes.Buffer
+		err := r.WriteText(&buf)
+		if err != nil {
+			return err
+		}
+
+		ctx.Set("Content-Type", ContentType)
+		_, err = ctx.Write(buf.Bytes())
+		return err
+	}
+}
+
+// Register registers the metrics route on the router, DefaultPath is used when path is empty.
+func (r *Registry) Register(router http.Router, path string) {
+	if path == "" {
+		path = DefaultPath
+	}
+	router.Get(path, r.Handler())
+}
+
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+var helpReplacer = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+
+var labelValueReplacer = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+
+func escapeHelp(s string) string {
+	return helpReplacer.Replace(s)
+}
+
+func escapeLabelValue(s string) string {
+	return labelValueReplacer.Replace(s)
+}
+
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
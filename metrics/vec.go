@@ -0,0 +1,254 @@
+package metrics
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// DefaultBuckets - upper bounds of histogram buckets in seconds, they fit latencies of network services
+var DefaultBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// vec - metric family partitioned by label values
+type vec struct {
+	sync.Mutex
+	name       string
+	help       string
+	labelNames []string
+}
+
+// key returns key of the series with the label values.
+func (v *vec) key(labelValues []string) string {
+	if len(labelValues) != len(v.labelNames) {
+		panic(fmt.Sprintf("metrics: %s expects %d label values, got %d", v.name, len(v.labelNames), len(labelValues)))
+	}
+	return strings.Join(labelValues, "\xff")
+}
+
+// labels returns sample labels with the extra label appended.
+func (v *vec) labels(labelValues []string, extra ...Label) []Label {
+	labels := make([]Label, 0, len(labelValues)+len(extra))
+	for i, name := range v.labelNames {
+		labels = append(labels, Label{Name: name, Value: labelValues[i]})
+	}
+	return append(labels, extra...)
+}
+
+type valueSeries struct {
+	labelValues []string
+	value       float64
+}
+
+// valueVec - family of counters or gauges
+type valueVec struct {
+	vec
+	typ    string
+	series map[string]*valueSeries
+}
+
+func (v *valueVec) add(delta float64, labelValues []string) {
+	key := v.key(labelValues)
+
+	v.Lock()
+	defer v.Unlock()
+
+	s, ok := v.series[key]
+	if !ok {
+		s = &valueSeries{labelValues: append([]string(nil), labelValues...)}
+		v.series[key] = s
+	}
+	s.value += delta
+}
+
+func (v *valueVec) set(value float64, labelValues []string) {
+	key := v.key(labelValues)
+
+	v.Lock()
+	defer v.Unlock()
+
+	s, ok := v.series[key]
+	if !ok {
+		s = &valueSeries{labelValues: append([]string(nil), labelValues...)}
+		v.series[key] = s
+	}
+	s.value = value
+}
+
+func (v *valueVec) Collect() []Family {
+	v.Lock()
+	defer v.Unlock()
+
+	keys := sortedKeys(v.series)
+	samples := make([]Sample, 0, len(keys))
+	for _, key := range keys {
+		s := v.series[key]
+		samples = append(samples, Sample{Name: v.name, Labels: v.labels(s.labelValues), Value: s.value})
+	}
+
+	return []Family{{Name: v.name, Help: v.help, Type: v.typ, Samples: samples}}
+}
+
+// CounterVec - counters partitioned by label values
+type CounterVec struct {
+	valueVec
+}
+
+// Inc increments the counter with the label values by 1.
+func (c *CounterVec) Inc(labelValues ...string) {
+	c.add(1, labelValues)
+}
+
+// Add adds delta to the counter with the label values, delta must not be negative.
+func (c *CounterVec) Add(delta float64, labelValues ...string) {
+	if delta < 0 {
+		panic(fmt.Sprintf("metrics: counter %s cannot decrease", c.name))
+	}
+	c.add(delta, labelValues)
+}
+
+// NewCounterVec - return CounterVec with the label names
+func NewCounterVec(name, help string, labelNames ...string) *CounterVec {
+	return &CounterVec{valueVec{
+		vec:    vec{name: name, help: help, labelNames: labelNames},
+		typ:    TypeCounter,
+		series: make(map[string]*valueSeries),
+	}}
+}
+
+// GaugeVec - gauges partitioned by label values
+type GaugeVec struct {
+	valueVec
+}
+
+// Set sets the gauge with the label values.
+func (g *GaugeVec) Set(value float64, labelValues ...string) {
+	g.set(value, labelValues)
+}
+
+// Add adds delta to the gauge with the label values.
+func (g *GaugeVec) Add(delta float64, labelValues ...string) {
+	g.add(delta, labelValues)
+}
+
+// Inc increments the gauge with the label values by 1.
+func (g *GaugeVec) Inc(labelValues ...string) {
+	g.add(1, labelValues)
+}
+
+// Dec decrements the gauge with the label values by 1.
+func (g *GaugeVec) Dec(labelValues ...string) {
+	g.add(-1, labelValues)
+}
+
+// NewGaugeVec - return GaugeVec with the label names
+func NewGaugeVec(name, help string, labelNames ...string) *GaugeVec {
+	return &GaugeVec{valueVec{
+		vec:    vec{name: name, help: help, labelNames: labelNames},
+		typ:    TypeGauge,
+		series: make(map[string]*valueSeries),
+	}}
+}
+
+type histogramSeries struct {
+	labelValues []string
+	counts      []uint64
+	count       uint64
+	sum         float64
+}
+
+// HistogramVec - histograms partitioned by label values
+type HistogramVec struct {
+	vec
+	buckets []float64
+	series  map[string]*histogramSeries
+}
+
+// Observe adds the value to the histogram with the label values.
+func (h *HistogramVec) Observe(value float64, labelValues ...string) {
+	key := h.key(labelValues)
+
+	h.Lock()
+	defer h.Unlock()
+
+	s, ok := h.series[key]
+	if !ok {
+		s = &histogramSeries{
+			labelValues: append([]string(nil), labelValues...),
+			counts:      make([]uint64, len(h.buckets)),
+		}
+		h.series[key] = s
+	}
+
+	i := sort.SearchFloat64s(h.buckets, value)
+	if i < len(h.buckets) {
+		s.counts[i]++
+	}
+	s.count++
+	s.sum += value
+}
+
+func (h *HistogramVec) Collect() []Family {
+	h.Lock()
+	defer h.Unlock()
+
+	keys := sortedKeys(h.series)
+	samples := make([]Sample, 0, len(keys)*(len(h.buckets)+3))
+	for _, key := range keys {
+		s := h.series[key]
+
+		var cumulative uint64
+		for i, bound := range h.buckets {
+			cumulative += s.counts[i]
+			samples = append(samples, Sample{
+				Name:   h.name + "_bucket",
+				Labels: h.labels(s.labelValues, Label{Name: "le", Value: formatFloat(bound)}),
+				Value:  float64(cumulative),
+			})
+		}
+		samples = append(samples,
+			Sample{
+				Name:   h.name + "_bucket",
+				Labels: h.labels(s.labelValues, Label{Name: "le", Value: formatFloat(math.Inf(1))}),
+				Value:  float64(s.count),
+			},
+			Sample{Name: h.name + "_sum", Labels: h.labels(s.labelValues), Value: s.sum},
+			Sample{Name: h.name + "_count", Labels: h.labels(s.labelValues), Value: float64(s.count)},
+		)
+	}
+
+	return []Family{{Name: h.name, Help: h.help, Type: TypeHistogram, Samples: samples}}
+}
+
+// NewHistogramVec - return HistogramVec with the bucket upper bounds and the label names,
+// DefaultBuckets are used when buckets are empty
+func NewHistogramVec(name, help string, buckets []float64, labelNames ...string) *HistogramVec {
+	if len(buckets) == 0 {
+		buckets = DefaultBuckets
+	}
+	buckets = append([]float64(nil), buckets...)
+	sort.Float64s(buckets)
+
+	return &HistogramVec{
+		vec:     vec{name: name, help: help, labelNames: labelNames},
+		buckets: buckets,
+		series:  make(map[string]*histogramSeries),
+	}
+}
+
+func sortedKeys(m interface{}) []string {
+	var keys []string
+	switch m := m.(type) {
+	case map[string]*valueSeries:
+		for key := range m {
+			keys = append(keys, key)
+		}
+	case map[string]*histogramSeries:
+		for key := range m {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,127 @@
+package openapi
+
+import (
+	"encoding/json"
+	nethttp "net/http"
+)
+
+// Document - OpenAPI document, fields not used by the generator are omitted
+type Document struct {
+	OpenAPI    string               `json:"openapi"`
+	Info       Info                 `json:"info"`
+	Servers    []Server             `json:"servers,omitempty"`
+	Paths      map[string]*PathItem `json:"paths"`
+	Components *Components          `json:"components,omitempty"`
+}
+
+// JSON returns the document encoded as JSON.
+func (d *Document) JSON() ([]byte, error) {
+	return json.Marshal(d)
+}
+
+// YAML returns the document encoded as YAML.
+func (d *Document) YAML() ([]byte, error) {
+	body, err := d.JSON()
+	if err != nil {
+		return nil, err
+	}
+	return jsonToYAML(body)
+}
+
+type Info struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+type Server struct {
+	URL string `json:"url"`
+}
+
+type PathItem struct {
+	Get     *OperationObject `json:"get,omitempty"`
+	Put     *OperationObject `json:"put,omitempty"`
+	Post    *OperationObject `json:"post,omitempty"`
+	Delete  *OperationObject `json:"delete,omitempty"`
+	Options *OperationObject `json:"options,omitempty"`
+	Head    *OperationObject `json:"head,omitempty"`
+	Patch   *OperationObject `json:"patch,omitempty"`
+	Trace   *OperationObject `json:"trace,omitempty"`
+}
+
+// set sets the operation of the method, false is returned for methods unsupported by OpenAPI, e.g. CONNECT.
+func (p *PathItem) set(method string, op *OperationObject) bool {
+	switch method {
+	case nethttp.MethodGet:
+		p.Get = op
+	case nethttp.MethodPut:
+		p.Put = op
+	case nethttp.MethodPost:
+		p.Post = op
+	case nethttp.MethodDelete:
+		p.Delete = op
+	case nethttp.MethodOptions:
+		p.Options = op
+	case nethttp.MethodHead:
+		p.Head = op
+	case nethttp.MethodPatch:
+		p.Patch = op
+	case nethttp.MethodTrace:
+		p.Trace = op
+	default:
+		return false
+	}
+	return true
+}
+
+// OperationObject - OpenAPI operation
+type OperationObject struct {
+	Tags        []string                   `json:"tags,omitempty"`
+	Summary     string                     `json:"summary,omitempty"`
+	Description string                     `json:"description,omitempty"`
+	OperationID string                     `json:"operationId,omitempty"`
+	Parameters  []*Parameter               `json:"parameters,omitempty"`
+	RequestBody *RequestBody               `json:"requestBody,omitempty"`
+	Responses   map[string]*ResponseObject `json:"responses"`
+	Deprecated  bool                       `json:"deprecated,omitempty"`
+}
+
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+type RequestBody struct {
+	Required bool                  `json:"required,omitempty"`
+	Content  map[string]*MediaType `json:"content"`
+}
+
+// ResponseObject - OpenAPI response
+type ResponseObject struct {
+	Description string                `json:"description"`
+	Content     map[string]*MediaType `json:"content,omitempty"`
+}
+
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+type Components struct {
+	Schemas map[string]*Schema `json:"schemas,omitempty"`
+}
+
+// Schema - OpenAPI schema object
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Nullable             bool               `json:"nullable,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+}
@@ -0,0 +1,7 @@
+package openapi
+
+// exported for tests of openapi_test package
+var (
+	JSONToYAML  = jsonToYAML
+	ConvertPath = convertPath
+)
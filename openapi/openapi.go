@@ -0,0 +1,336 @@
+package openapi
+
+import (
+	"github.com/ok93-01-18/go-ms-lib/servers/http"
+	nethttp "net/http"
+	"strconv"
+	"strings"
+)
+
+// Version - version of OpenAPI specification of generated documents
+const Version = "3.0.3"
+
+// MetaKey - key of Operation in route metadata
+const MetaKey = "openapi"
+
+// Defaults used for zero values of Config
+const (
+	DefaultPath     = "/openapi.json"
+	DefaultYAMLPath = "/openapi.yaml"
+)
+
+// Locations of parameters
+const (
+	InPath   = "path"
+	InQuery  = "query"
+	InHeader = "header"
+	InCookie = "cookie"
+)
+
+// Operation - description of the route
+type Operation struct {
+	Summary     string
+	Description string
+	Tags        []string
+
+	// OperationID - unique ID of the operation, route name is used when empty.
+	// IDs shared by several routes, e.g. the name of the route registered by All, are suffixed with the method.
+	OperationID string
+	Deprecated  bool
+
+	// Params - parameters of the operation, path parameters missed here are added from the route path.
+	Params []Param
+
+	// Query - struct value, which fields are query parameters named by "query" tag, e.g. the one passed to Context.BindQuery.
+	Query interface{}
+
+	// Headers - struct value, which fields are header parameters named by "header" tag.
+	Headers interface{}
+
+	// Request - value of request body type, e.g. the one passed to Context.BodyParser.
+	Request interface{}
+
+	// RequestContentType - content type of request body, "application/json" is used when empty.
+	RequestContentType string
+
+	// Responses - responses of the operation, "200 OK" without body is used when empty.
+	Responses []Response
+}
+
+// Param - parameter of the operation
+type Param struct {
+	Name        string
+	In          string
+	Description string
+	Required    bool
+
+	// Type - value of parameter type or reflect.Type, string is used when nil.
+	Type interface{}
+}
+
+// Response - response of the operation
+type Response struct {
+	Status      int
+	Description string
+
+	// Body - value of response body type, response has no body when nil.
+	Body interface{}
+
+	// ContentType - content type of response body, "application/json" is used when empty.
+	ContentType string
+}
+
+// Describe sets the operation as metadata of the last registered route of the router.
+//
+//	openapi.Describe(app.Get("/users/:id", handler), &openapi.Operation{Summary: "Get user"})
+func Describe(router http.Router, operation *Operation) http.Router {
+	return router.Meta(MetaKey, operation)
+}
+
+type Config struct {
+	Title       string
+	Version     string
+	Description string
+
+	// Servers - base URLs of the API.
+	Servers []string
+
+	// IncludeUndocumented - routes without Operation metadata are included too.
+	IncludeUndocumented bool
+
+	// Path - route of JSON document, DefaultPath is used when empty.
+	Path string
+
+	// YAMLPath - route of YAML document, DefaultYAMLPath is used when empty.
+	YAMLPath string
+}
+
+// Generate generates document of the routes, e.g. Router.Routes.
+func Generate(conf *Config, routes []http.Route) *Document {
+	doc := &Document{
+		OpenAPI: Version,
+		Info: Info{
+			Title:       conf.Title,
+			Version:     conf.Version,
+			Description: conf.Description,
+		},
+		Paths: make(map[string]*PathItem),
+	}
+	for _, url := range conf.Servers {
+		doc.Servers = append(doc.Servers, Server{URL: url})
+	}
+
+	documented := make([]http.Route, 0, len(routes))
+	operations := make([]*Operation, 0, len(routes))
+	for _, route := range routes {
+		// OpenAPI path items have no CONNECT operations
+		if route.Method == nethttp.MethodConnect {
+			continue
+		}
+
+		operation, ok := route.Meta[MetaKey].(*Operation)
+		if !ok {
+			if !conf.IncludeUndocumented {
+				continue
+			}
+			operation = &Operation{}
+		}
+		documented = append(documented, route)
+		operations = append(operations, operation)
+	}
+
+	ids := newOperationIDs(documented, operations)
+	schemas := newSchemaRegistry()
+	for i, route := range documented {
+		path, pathParams := convertPath(route.Path)
+		item, ok := doc.Paths[path]
+		if !ok {
+			item = &PathItem{}
+		}
+
+		op := newOperation(schemas, route, operations[i], pathParams)
+		op.OperationID = ids.unique(op.OperationID, route.Method)
+		if item.set(route.Method, op) {
+			doc.Paths[path] = item
+		}
+	}
+
+	if len(schemas.schemas) > 0 {
+		doc.Components = &Components{Schemas: schemas.schemas}
+	}
+	return doc
+}
+
+// Register registers routes of JSON and YAML documents of the router routes.
+// Document is generated on each request, so routes registered after Register are included.
+// Registered on the group, it documents routes under the group prefix only, see Router.Routes.
+func Register(router http.Router, conf *Config) {
+	jsonPath := conf.Path
+	if jsonPath == "" {
+		jsonPath = DefaultPath
+	}
+	yamlPath := conf.YAMLPath
+	if yamlPath == "" {
+		yamlPath = DefaultYAMLPath
+	}
+
+	router.Get(jsonPath, func(ctx http.Context) error {
+		return ctx.JSON(Generate(conf, router.Routes()))
+	})
+	router.Get(yamlPath, func(ctx http.Context) error {
+		body, err := Generate(conf, router.Routes()).YAML()
+		if err != nil {
+			return err
+		}
+
+		ctx.Set("Content-Type", "application/yaml")
+		_, err = ctx.Write(body)
+		return err
+	})
+}
+
+// operationIDs - operation IDs of the document, which are made unique
+type operationIDs struct {
+	counts map[string]int
+	used   map[string]bool
+}
+
+// newOperationIDs counts IDs of operations before they are made unique.
+func newOperationIDs(routes []http.Route, operations []*Operation) *operationIDs {
+	ids := &operationIDs{counts: make(map[string]int), used: make(map[string]bool)}
+	for i, operation := range operations {
+		id := operation.OperationID
+		if id == "" {
+			id = routes[i].Name
+		}
+		if id != "" {
+			ids.counts[id]++
+		}
+	}
+	return ids
+}
+
+// unique returns the ID suffixed with the method if it is shared by several operations,
+// a number is added if the suffixed ID is taken as well.
+func (o *operationIDs) unique(id, method string) string {
+	if id == "" {
+		return ""
+	}
+
+	if o.counts[id] > 1 {
+		id += "_" + strings.ToLower(method)
+	}
+	candidate := id
+	for n := 2; o.used[candidate]; n++ {
+		candidate = id + "_" + strconv.Itoa(n)
+	}
+	o.used[candidate] = true
+	return candidate
+}
+
+func newOperation(schemas *schemaRegistry, route http.Route, operation *Operation, pathParams []string) *OperationObject {
+	op := &OperationObject{
+		Summary:     operation.Summary,
+		Description: operation.Description,
+		Tags:        operation.Tags,
+		OperationID: operation.OperationID,
+		Deprecated:  operation.Deprecated,
+		Responses:   make(map[string]*ResponseObject),
+	}
+	if op.OperationID == "" {
+		op.OperationID = route.Name
+	}
+
+	declared := make(map[string]bool)
+	for _, param := range operation.Params {
+		if param.In == InPath {
+			declared[param.Name] = true
+		}
+		op.Parameters = append(op.Parameters, newParameter(schemas, param))
+	}
+	for _, name := range pathParams {
+		if !declared[name] {
+			op.Parameters = append(op.Parameters, newParameter(schemas, Param{Name: name, In: InPath}))
+		}
+	}
+	op.Parameters = append(op.Parameters, structParameters(schemas, operation.Query, InQuery)...)
+	op.Parameters = append(op.Parameters, structParameters(schemas, operation.Headers, InHeader)...)
+
+	if operation.Request != nil {
+		op.RequestBody = &RequestBody{
+			Required: true,
+			Content:  content(schemas, operation.Request, operation.RequestContentType),
+		}
+	}
+
+	for _, response := range operation.Responses {
+		description := response.Description
+		if description == "" {
+			description = nethttp.StatusText(response.Status)
+		}
+
+		r := &ResponseObject{Description: description}
+		if response.Body != nil {
+			r.Content = content(schemas, response.Body, response.ContentType)
+		}
+		op.Responses[strconv.Itoa(response.Status)] = r
+	}
+	if len(op.Responses) == 0 {
+		op.Responses["200"] = &ResponseObject{Description: nethttp.StatusText(nethttp.StatusOK)}
+	}
+
+	return op
+}
+
+func newParameter(schemas *schemaRegistry, param Param) *Parameter {
+	var schema *Schema
+	if param.Type == nil {
+		schema = &Schema{Type: "string"}
+	} else {
+		schema = schemas.schemaOf(param.Type)
+	}
+
+	return &Parameter{
+		Name:        param.Name,
+		In:          param.In,
+		Description: param.Description,
+		Required:    param.Required || param.In == InPath,
+		Schema:      schema,
+	}
+}
+
+func content(schemas *schemaRegistry, value interface{}, contentType string) map[string]*MediaType {
+	if contentType == "" {
+		contentType = "application/json"
+	}
+	return map[string]*MediaType{
+		contentType: {Schema: schemas.schemaOf(value)},
+	}
+}
+
+// convertPath converts route path to OpenAPI path template and returns names of path parameters.
+// Wildcards "*" and "+" become parameters "wildcard" and "wildcard1", "wildcard2" and so on.
+func convertPath(path string) (string, []string) {
+	segments := strings.Split(path, "/")
+	var params []string
+	wildcards := 0
+	for i, segment := range segments {
+		var name string
+		switch {
+		case strings.HasPrefix(segment, ":"):
+			name = strings.TrimSuffix(segment[1:], "?")
+		case segment == "*" || segment == "+":
+			name = "wildcard"
+			if wildcards > 0 {
+				name += strconv.Itoa(wildcards)
+			}
+			wildcards++
+		default:
+			continue
+		}
+
+		segments[i] = "{" + name + "}"
+		params = append(params, name)
+	}
+	return strings.Join(segments, "/"), params
+}
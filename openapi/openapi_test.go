@@ -0,0 +1,30 @@
+package openapi_test
+
+import (
+	"github.com/ok93-01-18/go-ms-lib/openapi"
+	"reflect"
+	"testing"
+)
+
+func TestConvertPath(t *testing.T) {
+	for _, test := range []struct {
+		path       string
+		wantPath   string
+		wantParams []string
+	}{
+		{path: "/users", wantPath: "/users"},
+		{path: "/users/:id", wantPath: "/users/{id}", wantParams: []string{"id"}},
+		{path: "/users/:id/items/:item", wantPath: "/users/{id}/items/{item}", wantParams: []string{"id", "item"}},
+		// OpenAPI has no optional path params, so the path with the param is documented
+		{path: "/users/:id?", wantPath: "/users/{id}", wantParams: []string{"id"}},
+		{path: "/files/*", wantPath: "/files/{wildcard}", wantParams: []string{"wildcard"}},
+		{path: "/files/+", wantPath: "/files/{wildcard}", wantParams: []string{"wildcard"}},
+		{path: "/a/*/b/+", wantPath: "/a/{wildcard}/b/{wildcard1}", wantParams: []string{"wildcard", "wildcard1"}},
+		{path: "/:a/:b?/*", wantPath: "/{a}/{b}/{wildcard}", wantParams: []string{"a", "b", "wildcard"}},
+	} {
+		path, params := openapi.ConvertPath(test.path)
+		if path != test.wantPath || !reflect.DeepEqual(params, test.wantParams) {
+			t.Errorf("convertPath(%q) = %q, %q, want %q, %q", test.path, path, params, test.wantPath, test.wantParams)
+		}
+	}
+}
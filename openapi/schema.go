@@ -0,0 +1,204 @@
+package openapi
+
+import (
+	"encoding"
+	"github.com/ok93-01-18/go-ms-lib/servers/http"
+	"reflect"
+	"strings"
+	"time"
+)
+
+var (
+	timeType          = reflect.TypeOf(time.Time{})
+	durationType      = reflect.TypeOf(time.Duration(0))
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// schemaRegistry - schemas of named struct types referenced from the document
+type schemaRegistry struct {
+	schemas map[string]*Schema
+	names   map[reflect.Type]string
+}
+
+// schemaOf returns schema of the value type, reflect.Type is accepted as well.
+func (r *schemaRegistry) schemaOf(value interface{}) *Schema {
+	t, ok := value.(reflect.Type)
+	if !ok {
+		t = reflect.TypeOf(value)
+	}
+	if t == nil {
+		return &Schema{}
+	}
+	return r.schema(t)
+}
+
+func (r *schemaRegistry) schema(t reflect.Type) *Schema {
+	if t.Kind() == reflect.Ptr {
+		s := r.schema(t.Elem())
+		if s.Ref != "" {
+			return s
+		}
+		s.Nullable = true
+		return s
+	}
+
+	switch t {
+	case timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case durationType:
+		return &Schema{Type: "integer", Format: "int64"}
+	}
+	if t.Kind() != reflect.Struct && t.Implements(textMarshalerType) {
+		return &Schema{Type: "string"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int8, reflect.Int16, reflect.Int32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int, reflect.Int64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer", Format: "int32", Minimum: new(float64)}
+	case reflect.Uint, reflect.Uint64, reflect.Uintptr:
+		return &Schema{Type: "integer", Format: "int64", Minimum: new(float64)}
+	case reflect.Float32:
+		return &Schema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: r.schema(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: r.schema(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return r.structSchema(t)
+		}
+		return &Schema{Ref: "#/components/schemas/" + r.register(t)}
+	}
+
+	// interface{} and unsupported kinds, any value is allowed
+	return &Schema{}
+}
+
+// register adds schema of the named struct type to components and returns its name.
+func (r *schemaRegistry) register(t reflect.Type) string {
+	if name, ok := r.names[t]; ok {
+		return name
+	}
+
+	name := t.Name()
+	if _, taken := r.schemas[name]; taken {
+		pkg := t.PkgPath()
+		name = pkg[strings.LastIndex(pkg, "/")+1:] + "." + name
+	}
+
+	// register before the schema is built, so recursive types refer to it
+	r.names[t] = name
+	r.schemas[name] = &Schema{}
+	*r.schemas[name] = *r.structSchema(t)
+
+	return name
+}
+
+// structSchema returns object schema with properties named as encoding/json does.
+func (r *schemaRegistry) structSchema(t reflect.Type) *Schema {
+	s := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+	r.addProperties(s, t)
+	return s
+}
+
+func (r *schemaRegistry) addProperties(s *Schema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name := strings.Split(tag, ",")[0]
+
+		if field.Anonymous && name == "" {
+			ft := field.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				r.addProperties(s, ft)
+				continue
+			}
+		}
+		if field.PkgPath != "" {
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+		s.Properties[name] = r.schema(field.Type)
+		if isRequired(field) {
+			s.Required = append(s.Required, name)
+		}
+	}
+}
+
+// isRequired returns true if the field has required validation rule.
+func isRequired(field reflect.StructField) bool {
+	for _, rule := range strings.Split(field.Tag.Get(http.ValidateTag), ",") {
+		if rule == "required" {
+			return true
+		}
+	}
+	return false
+}
+
+// structParameters returns parameters of exported fields of the struct value named by the tag of the location.
+func structParameters(schemas *schemaRegistry, value interface{}, in string) []*Parameter {
+	if value == nil {
+		return nil
+	}
+
+	t := reflect.TypeOf(value)
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var params []*Parameter
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+
+		name := field.Tag.Get(in)
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		params = append(params, newParameter(schemas, Param{
+			Name:     name,
+			In:       in,
+			Required: isRequired(field),
+			Type:     field.Type,
+		}))
+	}
+	return params
+}
+
+func newSchemaRegistry() *schemaRegistry {
+	return &schemaRegistry{
+		schemas: make(map[string]*Schema),
+		names:   make(map[reflect.Type]string),
+	}
+}
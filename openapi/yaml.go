@@ -0,0 +1,142 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+)
+
+// yamlNode - JSON value with object keys kept in order of the document
+type yamlNode struct {
+	// kind - '{', '[' or 0 for scalars
+	kind     byte
+	keys     []string
+	children []*yamlNode
+	scalar   string
+}
+
+// jsonToYAML converts JSON document to YAML block style keeping order of object keys.
+func jsonToYAML(data []byte) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	node, err := decodeYAMLNode(dec)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	writeYAMLNode(&buf, node, 0, false)
+	return buf.Bytes(), nil
+}
+
+func decodeYAMLNode(dec *json.Decoder) (*yamlNode, error) {
+	token, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch token := token.(type) {
+	case json.Delim:
+		node := &yamlNode{kind: '['}
+		if token == '{' {
+			node.kind = '{'
+		}
+
+		for dec.More() {
+			if node.kind == '{' {
+				key, err := dec.Token()
+				if err != nil {
+					return nil, err
+				}
+				node.keys = append(node.keys, key.(string))
+			}
+
+			child, err := decodeYAMLNode(dec)
+			if err != nil {
+				return nil, err
+			}
+			node.children = append(node.children, child)
+		}
+
+		// closing delimiter
+		_, err = dec.Token()
+		return node, err
+	case string:
+		return &yamlNode{scalar: yamlString(token)}, nil
+	case json.Number:
+		return &yamlNode{scalar: token.String()}, nil
+	case bool:
+		if token {
+			return &yamlNode{scalar: "true"}, nil
+		}
+		return &yamlNode{scalar: "false"}, nil
+	default:
+		return &yamlNode{scalar: "null"}, nil
+	}
+}
+
+// writeYAMLNode writes children of the node, the first line is not indented if inline is set,
+// so mapping items of sequences start on the line of the dash.
+func writeYAMLNode(buf *bytes.Buffer, node *yamlNode, indent int, inline bool) {
+	prefix := strings.Repeat("  ", indent)
+	for i, child := range node.children {
+		if i > 0 || !inline {
+			buf.WriteString(prefix)
+		}
+		if node.kind == '{' {
+			buf.WriteString(yamlString(node.keys[i]) + ":")
+		} else {
+			buf.WriteString("-")
+		}
+
+		switch {
+		case child.kind == 0:
+			buf.WriteString(" " + child.scalar + "\n")
+		case len(child.children) == 0 && child.kind == '{':
+			buf.WriteString(" {}\n")
+		case len(child.children) == 0:
+			buf.WriteString(" []\n")
+		case node.kind == '[' && child.kind == '{':
+			buf.WriteString(" ")
+			writeYAMLNode(buf, child, indent+1, true)
+		default:
+			buf.WriteString("\n")
+			writeYAMLNode(buf, child, indent+1, false)
+		}
+	}
+}
+
+// yamlString returns the string as plain scalar if it can't be read as another type or contains special characters,
+// otherwise as double-quoted scalar, which escapes are compatible with JSON.
+func yamlString(s string) string {
+	if isPlainYAML(s) {
+		return s
+	}
+
+	quoted, _ := json.Marshal(s)
+	return string(quoted)
+}
+
+func isPlainYAML(s string) bool {
+	if s == "" || strings.TrimSpace(s) != s {
+		return false
+	}
+
+	switch strings.ToLower(s) {
+	case "true", "false", "yes", "no", "on", "off", "y", "n", "null", "~":
+		return false
+	}
+
+	if strings.ContainsAny(s[:1], "-?:,[]{}#&*!|>'\"%@`0123456789.+") {
+		return false
+	}
+
+	for _, r := range s {
+		if r < 0x20 || r > 0x7e {
+			return false
+		}
+	}
+
+	return !strings.Contains(s, ": ") && !strings.Contains(s, " #") && !strings.HasSuffix(s, ":")
+}
@@ -0,0 +1,63 @@
+package openapi_test
+
+import (
+	"github.com/ok93-01-18/go-ms-lib/openapi"
+	"testing"
+)
+
+func TestJSONToYAML(t *testing.T) {
+	for _, test := range []struct {
+		name string
+		json string
+		want string
+	}{
+		{
+			name: "strings read as other types",
+			json: `{"a":"yes","b":"No","c":"null","d":"~","e":"123","f":"1.5","g":"+1"}`,
+			want: "a: \"yes\"\nb: \"No\"\nc: \"null\"\nd: \"~\"\ne: \"123\"\nf: \"1.5\"\ng: \"+1\"\n",
+		},
+		{
+			name: "strings with indicators",
+			json: `{"a":":","b":"-x","c":"key: value","d":"a #b","e":"ends:","f":"*ref","g":"[list]"}`,
+			want: "a: \":\"\nb: \"-x\"\nc: \"key: value\"\nd: \"a #b\"\ne: \"ends:\"\nf: \"*ref\"\ng: \"[list]\"\n",
+		},
+		{
+			name: "strings with spaces and escapes",
+			json: `{"a":"","b":" pad","c":"line\nbreak","d":"plain text","e":"x-y"}`,
+			want: "a: \"\"\nb: \" pad\"\nc: \"line\\nbreak\"\nd: plain text\ne: x-y\n",
+		},
+		{
+			name: "keys",
+			json: `{"yes":1,"-":2,"n":3,"/users/{id}":4}`,
+			want: "\"yes\": 1\n\"-\": 2\n\"n\": 3\n/users/{id}: 4\n",
+		},
+		{
+			name: "scalars",
+			json: `{"int":12,"float":1.5,"bool":true,"null":null}`,
+			want: "int: 12\nfloat: 1.5\nbool: true\n\"null\": null\n",
+		},
+		{
+			name: "empty maps and arrays",
+			json: `{"empty":{},"list":[],"nested":{"inner":{},"items":[{},[]]}}`,
+			want: "empty: {}\nlist: []\nnested:\n  inner: {}\n  items:\n    - {}\n    - []\n",
+		},
+		{
+			name: "maps in arrays",
+			json: `[{"a":1,"b":{"c":[{"d":1,"e":2}]}},[1,"two"]]`,
+			want: "- a: 1\n  b:\n    c:\n      - d: 1\n        e: 2\n-\n  - 1\n  - two\n",
+		},
+	} {
+		got, err := openapi.JSONToYAML([]byte(test.json))
+		if err != nil {
+			t.Errorf("%s: jsonToYAML() = %v", test.name, err)
+			continue
+		}
+		if string(got) != test.want {
+			t.Errorf("%s: jsonToYAML() =\n%s\nwant\n%s", test.name, got, test.want)
+		}
+	}
+
+	if _, err := openapi.JSONToYAML([]byte(`{"a":`)); err == nil {
+		t.Error("jsonToYAML() of invalid JSON returned no error")
+	}
+}
@@ -1,12 +1,24 @@
 package schedule
 
-import robficCron "github.com/robfig/cron/v3"
+import (
+	"context"
+	robficCron "github.com/robfig/cron/v3"
+)
 
 type Croner interface {
 	Start()
 	AddFunc(string, func()) error
 }
 
+// StoppableCroner - Croner which can be stopped gracefully, CronManager waits for its running jobs on stop
+type StoppableCroner interface {
+	Croner
+
+	// Stop stops the scheduler if it is running and returns a context
+	// which is done when all running jobs have completed.
+	Stop() context.Context
+}
+
 type Cron struct {
 	cron *robficCron.Cron
 }
@@ -15,6 +27,10 @@ func (s *Cron) Start() {
 	s.cron.Start()
 }
 
+func (s *Cron) Stop() context.Context {
+	return s.cron.Stop()
+}
+
 func (s *Cron) AddFunc(spec string, f func()) error {
 	_, err := s.cron.AddFunc(spec, f)
 	return err
@@ -1,7 +1,11 @@
 package schedule
 
 import (
+	"context"
+	"errors"
 	"github.com/ok93-01-18/go-ms-lib/log"
+	"sync"
+	"time"
 )
 
 type Operation struct {
@@ -12,16 +16,38 @@ type Operation struct {
 	IsNeedFunc func() (bool, error)
 }
 
+// OperationStats - statistics of operation runs
+type OperationStats struct {
+	Name                string
+	Runs                int64
+	Failures            int64
+	ConsecutiveFailures int64
+	LastRun             time.Time
+	LastDuration        time.Duration
+	TotalDuration       time.Duration
+	LastError           error
+}
+
+// ErrNotStoppable - error of stopping manager whose Croner is not StoppableCroner
+var ErrNotStoppable = errors.New("schedule: croner is not stoppable")
+
 type CronManager struct {
+	sync.RWMutex
 	appLogger  log.Logger
 	cron       Croner
 	operations *[]Operation
+	stats      map[string]*OperationStats
+	running    bool
 }
 
 func (m *CronManager) Init() error {
 
 	lenOperations := len(*m.operations)
 	if lenOperations == 0 {
+		// nothing is scheduled, but the manager is healthy and can be stopped as usual
+		m.Lock()
+		m.running = true
+		m.Unlock()
 		return nil
 	}
 
@@ -39,7 +65,7 @@ func (m *CronManager) Init() error {
 			}
 
 			if isNeed {
-				err = operation.Task.Do()
+				err = m.run(operation)
 				if err != nil {
 					return err
 				}
@@ -48,7 +74,7 @@ func (m *CronManager) Init() error {
 		}
 
 		err = m.cron.AddFunc(operation.Interval, func() {
-			err = operation.Task.Do()
+			err := m.run(operation)
 			if err != nil {
 				m.appLogger.Errorf(log.TypeApp, "%s error: %v", operation.Name, err)
 			}
@@ -61,13 +87,90 @@ func (m *CronManager) Init() error {
 
 	m.cron.Start()
 
+	m.Lock()
+	m.running = true
+	m.Unlock()
+
 	return nil
 }
 
+// run executes the operation task and records its statistics.
+func (m *CronManager) run(operation Operation) error {
+	start := time.Now()
+	err := operation.Task.Do()
+	duration := time.Since(start)
+
+	m.Lock()
+	defer m.Unlock()
+
+	stats, ok := m.stats[operation.Name]
+	if !ok {
+		stats = &OperationStats{Name: operation.Name}
+		m.stats[operation.Name] = stats
+	}
+
+	stats.Runs++
+	stats.LastRun = start
+	stats.LastDuration = duration
+	stats.TotalDuration += duration
+	stats.LastError = err
+	if err != nil {
+		stats.Failures++
+		stats.ConsecutiveFailures++
+	} else {
+		stats.ConsecutiveFailures = 0
+	}
+
+	return err
+}
+
+// IsRunning returns true if operations are scheduled, i.e. Init succeeded and Stop wasn't called.
+func (m *CronManager) IsRunning() bool {
+	m.RLock()
+	defer m.RUnlock()
+
+	return m.running
+}
+
+// Stats returns statistics of operations which have been run at least once.
+func (m *CronManager) Stats() []OperationStats {
+	m.RLock()
+	defer m.RUnlock()
+
+	stats := make([]OperationStats, 0, len(m.stats))
+	for _, operation := range *m.operations {
+		if s, ok := m.stats[operation.Name]; ok {
+			stats = append(stats, *s)
+		}
+	}
+	return stats
+}
+
+// Stop stops scheduling of operations and waits for running ones until the context is done.
+// Croner which is not StoppableCroner keeps scheduling, only IsRunning is reset and ErrNotStoppable is returned.
+func (m *CronManager) Stop(ctx context.Context) error {
+	m.Lock()
+	m.running = false
+	m.Unlock()
+
+	cron, ok := m.cron.(StoppableCroner)
+	if !ok {
+		return ErrNotStoppable
+	}
+
+	select {
+	case <-cron.Stop().Done():
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
 func NewCronManager(appLogger log.Logger, cron Croner, operations *[]Operation) *CronManager {
 	return &CronManager{
 		appLogger:  appLogger,
 		cron:       cron,
 		operations: operations,
+		stats:      make(map[string]*OperationStats),
 	}
 }
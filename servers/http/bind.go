@@ -0,0 +1,43 @@
+package http
+
+import (
+	"github.com/ok93-01-18/go-ms-lib/servers/http/internal/binding"
+	"net/url"
+)
+
+// Tags of Context.BindQuery, Context.BindParams and Context.BindHeaders
+const (
+	QueryTag  = "query"
+	ParamsTag = "params"
+	HeaderTag = "header"
+)
+
+// bindValues binds values to the struct pointed by out by the tag and validates it,
+// names of HeaderTag are case-insensitive.
+func bindValues(out interface{}, values map[string][]string, tag string) error {
+	return validationError(binding.Bind(out, values, tag, tag == HeaderTag))
+}
+
+func bindQuery(ctx Context, out interface{}) error {
+	values, err := url.ParseQuery(ctx.Request().QueryString())
+	if err != nil {
+		return BadRequest("invalid query string")
+	}
+	return bindValues(out, values, QueryTag)
+}
+
+func bindParams(params map[string]string, out interface{}) error {
+	values := make(map[string][]string, len(params))
+	for key, value := range params {
+		values[key] = []string{value}
+	}
+	return bindValues(out, values, ParamsTag)
+}
+
+func bindHeaders(ctx Context, out interface{}) error {
+	values := make(map[string][]string)
+	ctx.Request().VisitHeaders(func(key, value string) {
+		values[key] = append(values[key], value)
+	})
+	return bindValues(out, values, HeaderTag)
+}
@@ -0,0 +1,157 @@
+package http_test
+
+import (
+	"github.com/ok93-01-18/go-ms-lib/servers/http"
+	"github.com/ok93-01-18/go-ms-lib/servers/http/httpassert"
+	"github.com/ok93-01-18/go-ms-lib/servers/http/internal/servertest"
+	nethttp "net/http"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+type searchQuery struct {
+	Query  string    `query:"q" validate:"required"`
+	Page   int       `query:"page" validate:"min=1"`
+	Limit  *int      `query:"limit" validate:"max=100"`
+	Sort   string    `query:"sort" validate:"oneof=asc desc"`
+	IDs    []int     `query:"ids"`
+	Since  time.Time `query:"since"`
+	Strict bool      `query:"strict"`
+}
+
+// bindError binds the error details as violations or responds with the bound value.
+func bindError(ctx http.Context, out interface{}, err error) error {
+	if err != nil {
+		httpErr := http.AsHTTPError(err)
+		return ctx.Status(httpErr.Status).JSON(httpErr.Details)
+	}
+	return ctx.JSON(out)
+}
+
+func TestContext_BindQuery(t *testing.T) {
+	servertest.Run(t, func(t *testing.T, server http.Server) {
+		server.Get("/search", func(ctx http.Context) error {
+			var query searchQuery
+			return bindError(ctx, &query, ctx.BindQuery(&query))
+		})
+
+		var query searchQuery
+		httpassert.Do(t, server, httpassert.NewRequest(nethttp.MethodGet,
+			"/search?q=go&page=2&limit=10&sort=asc&ids=1,2&since=2024-01-02&strict=true", nil)).
+			Status(http.StatusOK).
+			Decode(&query)
+
+		limit := 10
+		want := searchQuery{
+			Query:  "go",
+			Page:   2,
+			Limit:  &limit,
+			Sort:   "asc",
+			IDs:    []int{1, 2},
+			Since:  time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+			Strict: true,
+		}
+		if !reflect.DeepEqual(query, want) {
+			t.Errorf("query = %+v, want %+v", query, want)
+		}
+	})
+}
+
+func TestContext_BindQueryTypeViolations(t *testing.T) {
+	servertest.Run(t, func(t *testing.T, server http.Server) {
+		server.Get("/search", func(ctx http.Context) error {
+			var query searchQuery
+			return bindError(ctx, &query, ctx.BindQuery(&query))
+		})
+
+		httpassert.Do(t, server, httpassert.NewRequest(nethttp.MethodGet, "/search?q=go&page=first&limit=ten", nil)).
+			Status(http.StatusUnprocessableEntity).
+			JSON(`[
+				{"field":"page","rule":"type","message":"invalid value: invalid syntax"},
+				{"field":"limit","rule":"type","message":"invalid value: invalid syntax"}
+			]`)
+	})
+}
+
+func TestContext_BindQueryRuleViolations(t *testing.T) {
+	servertest.Run(t, func(t *testing.T, server http.Server) {
+		server.Get("/search", func(ctx http.Context) error {
+			var query searchQuery
+			return bindError(ctx, &query, ctx.BindQuery(&query))
+		})
+
+		// absent page and sort violate their rules, nil limit is not checked
+		httpassert.Do(t, server, httpassert.NewRequest(nethttp.MethodGet, "/search", nil)).
+			Status(http.StatusUnprocessableEntity).
+			JSON(`[
+				{"field":"q","rule":"required","message":"is required"},
+				{"field":"page","rule":"min","message":"must be at least 1"},
+				{"field":"sort","rule":"oneof","message":"must be one of asc, desc"}
+			]`)
+		httpassert.Do(t, server, httpassert.NewRequest(nethttp.MethodGet, "/search?q=go&page=1&sort=desc&limit=101", nil)).
+			Status(http.StatusUnprocessableEntity).
+			JSON(`[{"field":"limit","rule":"max","message":"must be at most 100"}]`)
+	})
+}
+
+func TestContext_BindHeaders(t *testing.T) {
+	type headers struct {
+		RequestID string `header:"x-request-id" validate:"required"`
+		Retries   int    `header:"X-Retries"`
+	}
+
+	servertest.Run(t, func(t *testing.T, server http.Server) {
+		server.Get("/", func(ctx http.Context) error {
+			var h headers
+			return bindError(ctx, &h, ctx.BindHeaders(&h))
+		})
+
+		req := httpassert.NewRequest(nethttp.MethodGet, "/", nil)
+		req.Header.Set("X-Request-ID", "abc")
+		req.Header.Set("X-Retries", "3")
+		httpassert.Do(t, server, req).
+			Status(http.StatusOK).
+			JSON(`{"RequestID":"abc","Retries":3}`)
+	})
+}
+
+func TestContext_BindParams(t *testing.T) {
+	type params struct {
+		Name string `params:"name" validate:"min=3"`
+		ID   int    `params:"id"`
+	}
+
+	servertest.Run(t, func(t *testing.T, server http.Server) {
+		var mu sync.Mutex
+		var bound []params
+		server.Get("/users/:name/:id", func(ctx http.Context) error {
+			var p params
+			err := ctx.BindParams(&p)
+			if err == nil {
+				mu.Lock()
+				bound = append(bound, p)
+				mu.Unlock()
+			}
+			return bindError(ctx, &p, err)
+		})
+
+		httpassert.Do(t, server, httpassert.NewRequest(nethttp.MethodGet, "/users/alice/1", nil)).
+			Status(http.StatusOK).
+			JSON(`{"Name":"alice","ID":1}`)
+		httpassert.Do(t, server, httpassert.NewRequest(nethttp.MethodGet, "/users/bobby/2", nil)).
+			Status(http.StatusOK)
+		httpassert.Do(t, server, httpassert.NewRequest(nethttp.MethodGet, "/users/al/x", nil)).
+			Status(http.StatusUnprocessableEntity).
+			JSON(`[{"field":"id","rule":"type","message":"invalid value: invalid syntax"}]`)
+
+		// bound values must not refer to buffers reused by later requests
+		want := []params{{Name: "alice", ID: 1}, {Name: "bobby", ID: 2}}
+		mu.Lock()
+		defer mu.Unlock()
+		if !reflect.DeepEqual(bound, want) {
+			t.Errorf("bound = %+v, want %+v", bound, want)
+		}
+	})
+}
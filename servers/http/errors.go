@@ -0,0 +1,192 @@
+package http
+
+import (
+	"errors"
+	"github.com/gofiber/fiber/v2"
+	"github.com/ok93-01-18/go-ms-lib/log"
+	nethttp "net/http"
+)
+
+// HTTPError - error with HTTP status, which is rendered by ErrorHandler
+type HTTPError struct {
+	// Status - HTTP status code of the response
+	Status int `json:"status"`
+
+	// Code - application specific error code
+	Code string `json:"code,omitempty"`
+
+	// Message - human-readable message for the client
+	Message string `json:"message"`
+
+	// Details - additional data for the client, e.g. field violations
+	Details interface{} `json:"details,omitempty"`
+
+	// Err - wrapped cause, it is logged but never sent to the client
+	Err error `json:"-"`
+}
+
+func (e *HTTPError) Error() string {
+	if e.Err != nil {
+		return e.Message + ": " + e.Err.Error()
+	}
+	return e.Message
+}
+
+func (e *HTTPError) Unwrap() error {
+	return e.Err
+}
+
+// WithCode returns copy of the error with the application specific code.
+func (e *HTTPError) WithCode(code string) *HTTPError {
+	c := *e
+	c.Code = code
+	return &c
+}
+
+// WithDetails returns copy of the error with the details.
+func (e *HTTPError) WithDetails(details interface{}) *HTTPError {
+	c := *e
+	c.Details = details
+	return &c
+}
+
+// Wrap returns copy of the error with the cause.
+func (e *HTTPError) Wrap(err error) *HTTPError {
+	c := *e
+	c.Err = err
+	return &c
+}
+
+// NewError - return HTTPError with the status, the message defaults to the status text
+func NewError(status int, message ...string) *HTTPError {
+	e := &HTTPError{
+		Status:  status,
+		Message: nethttp.StatusText(status),
+	}
+	if len(message) > 0 {
+		e.Message = message[0]
+	}
+	return e
+}
+
+func BadRequest(message ...string) *HTTPError {
+	return NewError(StatusBadRequest, message...)
+}
+
+func Unauthorized(message ...string) *HTTPError {
+	return NewError(StatusUnauthorized, message...)
+}
+
+func Forbidden(message ...string) *HTTPError {
+	return NewError(StatusForbidden, message...)
+}
+
+func NotFound(message ...string) *HTTPError {
+	return NewError(StatusNotFound, message...)
+}
+
+func MethodNotAllowed(message ...string) *HTTPError {
+	return NewError(StatusMethodNotAllowed, message...)
+}
+
+func Conflict(message ...string) *HTTPError {
+	return NewError(StatusConflict, message...)
+}
+
+func UnprocessableEntity(message ...string) *HTTPError {
+	return NewError(StatusUnprocessableEntity, message...)
+}
+
+func TooManyRequests(message ...string) *HTTPError {
+	return NewError(StatusTooManyRequests, message...)
+}
+
+func InternalServerError(message ...string) *HTTPError {
+	return NewError(StatusInternalServerError, message...)
+}
+
+func ServiceUnavailable(message ...string) *HTTPError {
+	return NewError(StatusServiceUnavailable, message...)
+}
+
+// AsHTTPError converts the error returned by handlers to HTTPError.
+// Errors without HTTP status are wrapped by InternalServerError, so their text is not sent to the client.
+func AsHTTPError(err error) *HTTPError {
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr
+	}
+
+	var fiberErr *fiber.Error
+	if errors.As(err, &fiberErr) {
+		return NewError(fiberErr.Code, fiberErr.Message)
+	}
+
+	return InternalServerError().Wrap(err)
+}
+
+// ErrorStatusCode returns HTTP status code carried by the error returned from handlers,
+// StatusInternalServerError is returned for errors without status code.
+func ErrorStatusCode(err error) int {
+	return AsHTTPError(err).Status
+}
+
+// ErrorHandler - handler of errors returned by handlers
+type ErrorHandler func(Context, error) error
+
+// ErrorFormat - format of error responses
+type ErrorFormat string
+
+const (
+	// ErrorFormatJSON - HTTPError is sent as JSON object
+	ErrorFormatJSON ErrorFormat = "json"
+
+	// ErrorFormatProblem - HTTPError is sent as RFC 7807 application/problem+json object
+	ErrorFormatProblem = "problem"
+)
+
+type ErrorHandlerConfig struct {
+	// Format of error responses, ErrorFormatJSON is used when empty.
+	Format ErrorFormat
+
+	// Logger receives server errors (5xx) to the app channel, they are not logged when nil.
+	Logger log.Logger
+}
+
+// problem - RFC 7807 problem details
+type problem struct {
+	Type     string      `json:"type"`
+	Title    string      `json:"title"`
+	Status   int         `json:"status"`
+	Detail   string      `json:"detail,omitempty"`
+	Instance string      `json:"instance,omitempty"`
+	Code     string      `json:"code,omitempty"`
+	Details  interface{} `json:"details,omitempty"`
+}
+
+// NewErrorHandler - return ErrorHandler which renders errors as HTTPError
+func NewErrorHandler(conf *ErrorHandlerConfig) ErrorHandler {
+	return func(ctx Context, err error) error {
+		httpErr := AsHTTPError(err)
+
+		if httpErr.Status >= StatusInternalServerError && conf.Logger != nil {
+			conf.Logger.Errorf(log.TypeApp, "%s %s: %v", ctx.Method(), ctx.Request().RequestURI(), err)
+		}
+
+		if conf.Format == ErrorFormatProblem {
+			err = ctx.Status(httpErr.Status).JSON(&problem{
+				Type:     "about:blank",
+				Title:    nethttp.StatusText(httpErr.Status),
+				Status:   httpErr.Status,
+				Detail:   httpErr.Message,
+				Instance: ctx.Request().Path(),
+				Code:     httpErr.Code,
+				Details:  httpErr.Details,
+			})
+			ctx.Set("Content-Type", "application/problem+json")
+			return err
+		}
+
+		return ctx.Status(httpErr.Status).JSON(httpErr)
+	}
+}
@@ -1,60 +1,251 @@
 package http
 
 import (
+	"bufio"
+	"context"
+	"crypto/x509"
+	"encoding/xml"
+	"errors"
 	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+	"github.com/ok93-01-18/go-ms-lib/servers/http/internal/binding"
+	"github.com/ok93-01-18/go-ms-lib/servers/http/internal/response"
+	"github.com/ok93-01-18/go-ms-lib/views"
 	"github.com/valyala/fasthttp"
+	"io"
+	stdlog "log"
+	"mime/multipart"
 	"net"
+	nethttp "net/http"
+	"net/url"
+	"strings"
+	"time"
 )
 
 type FiberApp struct {
-	app *fiber.App
+	hooks
+	app          *fiber.App
+	routes       *routeScope
+	errorHandler ErrorHandler
+	views        views.Engine
+	eventStreams eventStreams
+	websockets   websockets
+}
+
+// fiberAppLocal - key of the request local holding the wrapper of the app, so contexts can reach its settings
+const fiberAppLocal = "http.fiberApp"
+
+// fiberAppOf returns wrapper of the app serving the request,
+// nil is returned for apps not wrapped by NewFiberServer.
+func fiberAppOf(ctx *fiber.Ctx) *FiberApp {
+	s, _ := ctx.Locals(fiberAppLocal).(*FiberApp)
+	return s
 }
 
 func (s *FiberApp) Get(path string, handlers ...Handler) Router {
 	s.app.Get(path, FiberWrapHandlers(handlers...)...)
+	s.routes.add(path, fiber.MethodGet)
 	return s
 }
 
 func (s *FiberApp) Head(path string, handlers ...Handler) Router {
 	s.app.Head(path, FiberWrapHandlers(handlers...)...)
+	s.routes.add(path, fiber.MethodHead)
 	return s
 }
 
 func (s *FiberApp) Post(path string, handlers ...Handler) Router {
 	s.app.Post(path, FiberWrapHandlers(handlers...)...)
+	s.routes.add(path, fiber.MethodPost)
 	return s
 }
 
 func (s *FiberApp) Options(path string, handlers ...Handler) Router {
 	s.app.Options(path, FiberWrapHandlers(handlers...)...)
+	s.routes.add(path, fiber.MethodOptions)
 	return s
 }
 
 func (s *FiberApp) Delete(path string, handlers ...Handler) Router {
 	s.app.Delete(path, FiberWrapHandlers(handlers...)...)
+	s.routes.add(path, fiber.MethodDelete)
+	return s
+}
+
+func (s *FiberApp) Put(path string, handlers ...Handler) Router {
+	s.app.Put(path, FiberWrapHandlers(handlers...)...)
+	s.routes.add(path, fiber.MethodPut)
+	return s
+}
+
+func (s *FiberApp) Patch(path string, handlers ...Handler) Router {
+	s.app.Patch(path, FiberWrapHandlers(handlers...)...)
+	s.routes.add(path, fiber.MethodPatch)
+	return s
+}
+
+func (s *FiberApp) Connect(path string, handlers ...Handler) Router {
+	s.app.Connect(path, FiberWrapHandlers(handlers...)...)
+	s.routes.add(path, fiber.MethodConnect)
+	return s
+}
+
+func (s *FiberApp) Trace(path string, handlers ...Handler) Router {
+	s.app.Trace(path, FiberWrapHandlers(handlers...)...)
+	s.routes.add(path, fiber.MethodTrace)
+	return s
+}
+
+func (s *FiberApp) All(path string, handlers ...Handler) Router {
+	s.app.All(path, FiberWrapHandlers(handlers...)...)
+	s.routes.add(path, allMethods...)
+	return s
+}
+
+func (s *FiberApp) Add(method, path string, handlers ...Handler) Router {
+	s.app.Add(method, path, FiberWrapHandlers(handlers...)...)
+	s.routes.add(path, strings.ToUpper(method))
 	return s
 }
 
 func (s *FiberApp) Use(args ...interface{}) Router {
-	s.app.Use(args)
+	s.app.Use(fiberUseArgs(args)...)
+	return s
+}
+
+func (s *FiberApp) WebSocket(path string, handler WebSocketHandler) Router {
+	s.app.Get(path, fiberWebSocketHandler(handler))
+	s.routes.add(path, fiber.MethodGet)
 	return s
 }
 
 func (s *FiberApp) Group(prefix string, handlers ...Handler) Router {
 	gr := s.app.Group(prefix, FiberWrapHandlers(handlers...)...)
-	return NewFiberGroup(gr)
+	return newFiberGroup(gr, s.routes.table)
+}
+
+func (s *FiberApp) Name(name string) Router {
+	s.routes.name(name)
+	return s
+}
+
+func (s *FiberApp) Meta(key string, value interface{}) Router {
+	s.routes.meta(key, value)
+	return s
+}
+
+// Routes - routes registered directly on fiber.App are not included.
+func (s *FiberApp) Routes() []Route {
+	return s.routes.list()
 }
 
 func (s *FiberApp) Listener(ln net.Listener) error {
+	err := s.executeStartup()
+	if err != nil {
+		return err
+	}
 	return s.app.Listener(ln)
 }
 
+func (s *FiberApp) ListenTLS(addr, certFile, keyFile string) error {
+	return listenTLS(s, addr, &CertReloaderConfig{CertFile: certFile, KeyFile: keyFile})
+}
+
+func (s *FiberApp) ListenMutualTLS(addr, certFile, keyFile, clientCAFile string) error {
+	return listenTLS(s, addr, &CertReloaderConfig{CertFile: certFile, KeyFile: keyFile, ClientCAFile: clientCAFile})
+}
+
+func (s *FiberApp) ListenTLSReloader(addr string, reloader *CertReloader) error {
+	return listenTLSReloader(s, addr, reloader)
+}
+
 func (s *FiberApp) Listen(addr string) error {
+	err := s.executeStartup()
+	if err != nil {
+		return err
+	}
 	return s.app.Listen(addr)
 }
 
 func (s *FiberApp) Shutdown() error {
-	return s.app.Shutdown()
+	return s.ShutdownWithContext(context.Background())
+}
+
+// ShutdownWithContext - fasthttp can't interrupt active connections,
+// so they are left to finish in background when the context is done.
+func (s *FiberApp) ShutdownWithContext(ctx context.Context) error {
+	s.eventStreams.shutdown()
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- s.app.Shutdown()
+	}()
+
+	var err error
+	select {
+	case err = <-errCh:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	wsErr := s.websockets.shutdown(ctx)
+	hooksErr := s.executeShutdown(ctx)
+	if err != nil {
+		return err
+	}
+	if wsErr != nil {
+		return wsErr
+	}
+	return hooksErr
+}
+
+// SetErrorHandler - errors of routes registered on fiber.App before NewFiberServer are not handled.
+func (s *FiberApp) SetErrorHandler(handler ErrorHandler) {
+	s.errorHandler = handler
+}
+
+func (s *FiberApp) SetViews(engine views.Engine) {
+	s.views = engine
+}
+
+// handleError - first middleware of the app, which passes errors of next handlers to the error handler.
+// Without the error handler HTTPError is converted to fiber.Error, so fiber responds with its status and message.
+// It also stores the wrapper in request locals for contexts.
+func (s *FiberApp) handleError(ctx *fiber.Ctx) error {
+	ctx.Locals(fiberAppLocal, s)
+
+	err := ctx.Next()
+	if err == nil {
+		return nil
+	}
+
+	if s.errorHandler != nil {
+		err = s.errorHandler(newFiberContext(ctx), err)
+		if err == nil {
+			return nil
+		}
+	}
+
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) {
+		return fiber.NewError(httpErr.Status, httpErr.Message)
+	}
+	return err
+}
+
+func (s *FiberApp) Test(req *nethttp.Request, timeout time.Duration) (*nethttp.Response, error) {
+	msTimeout := -1
+	if timeout > 0 {
+		msTimeout = int((timeout + time.Millisecond - 1) / time.Millisecond)
+	}
+	return s.app.Test(req, msTimeout)
+}
+
+func (s *FiberApp) ShutdownWithTimeout(timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	return s.ShutdownWithContext(ctx)
 }
 
 func FiberWrapHandlers(handlers ...Handler) []fiber.Handler {
@@ -69,15 +260,78 @@ func FiberWrapHandlers(handlers ...Handler) []fiber.Handler {
 	return fiberHandlers
 }
 
+// fiberWebSocketHandler - upgrades the request, the handler is run on the connection hijacked after the response is sent.
+// Connections of apps not wrapped by NewFiberServer are not closed on shutdown.
+func fiberWebSocketHandler(handler WebSocketHandler) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		accept, err := websocketAccept(newFiberContext(c))
+		if err != nil {
+			return err
+		}
+
+		req := &websocketRequest{
+			params: make(map[string]string, len(c.Route().Params)),
+			locals: make(map[string]interface{}),
+			ip:     c.IP(),
+		}
+		for _, name := range c.Route().Params {
+			req.params[name] = utils.CopyString(c.Params(name))
+		}
+		c.Context().VisitUserValues(func(key []byte, value interface{}) {
+			if string(key) != fiberAppLocal {
+				req.locals[string(key)] = value
+			}
+		})
+		req.query, _ = url.ParseQuery(string(c.Request().URI().QueryString()))
+
+		conns := &websockets{}
+		if s := fiberAppOf(c); s != nil {
+			conns = &s.websockets
+		}
+
+		c.Set("Upgrade", "websocket")
+		c.Set("Connection", "Upgrade")
+		c.Set("Sec-WebSocket-Accept", accept)
+		c.Status(StatusSwitchingProtocols)
+		c.Context().Hijack(func(conn net.Conn) {
+			conns.serve(newWebsocketConn(conn, bufio.NewReader(conn), req), handler)
+		})
+		return nil
+	}
+}
+
+// fiberUseArgs - converts Handler args of Use to fiber handlers, other args are passed as is
+func fiberUseArgs(args []interface{}) []interface{} {
+	fiberArgs := make([]interface{}, 0, len(args))
+	for _, arg := range args {
+		switch arg := arg.(type) {
+		case Handler:
+			fiberArgs = append(fiberArgs, FiberWrapHandlers(arg)[0])
+		case func(Context) error:
+			fiberArgs = append(fiberArgs, FiberWrapHandlers(arg)[0])
+		case func(*fiber.Ctx) error:
+			fiberArgs = append(fiberArgs, fiber.Handler(arg))
+		default:
+			fiberArgs = append(fiberArgs, arg)
+		}
+	}
+
+	return fiberArgs
+}
+
 // NewFiberServer - return wrapper of Fiber App
 func NewFiberServer(f *fiber.App) Server {
-	return &FiberApp{app: f}
+	s := &FiberApp{app: f, routes: newRouteScope(&routeTable{}, "")}
+	f.Use(s.handleError)
+
+	return s
 }
 
 // FiberContext - wrapper on context fiber lib
 type FiberContext struct {
-	context *fiber.Ctx
-	request Request
+	context  *fiber.Ctx
+	request  Request
+	response Response
 }
 
 func (f *FiberContext) IP() string {
@@ -117,6 +371,19 @@ func (f *FiberContext) Request() Request {
 	return f.request
 }
 
+func (f *FiberContext) Response() Response {
+	return f.response
+}
+
+func (f *FiberContext) Context() context.Context {
+	return f.context.UserContext()
+}
+
+func (f *FiberContext) Route() Route {
+	route := f.context.Route()
+	return Route{Method: route.Method, Path: route.Path}
+}
+
 func (f *FiberContext) Writef(s string, a ...interface{}) (int, error) {
 	return f.context.Writef(s, a...)
 }
@@ -129,6 +396,23 @@ func (f *FiberContext) BodyParser(out interface{}) error {
 	return f.context.BodyParser(out)
 }
 
+func (f *FiberContext) BindQuery(out interface{}) error {
+	return bindQuery(f, out)
+}
+
+// BindParams - values are copied, because fiber params refer to the request buffer, which is reused after the request.
+func (f *FiberContext) BindParams(out interface{}) error {
+	params := f.context.AllParams()
+	for key, value := range params {
+		params[key] = utils.CopyString(value)
+	}
+	return bindParams(params, out)
+}
+
+func (f *FiberContext) BindHeaders(out interface{}) error {
+	return bindHeaders(f, out)
+}
+
 func (f *FiberContext) Next() error {
 	return f.context.Next()
 }
@@ -153,15 +437,111 @@ func (f *FiberContext) Params(key string, defaultValue ...string) string {
 	return f.context.Params(key, defaultValue...)
 }
 
+func (f *FiberContext) JSON(data interface{}) error {
+	return f.context.JSON(data)
+}
+
+func (f *FiberContext) XML(data interface{}) error {
+	raw, err := xml.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	f.context.Set(fiber.HeaderContentType, fiber.MIMEApplicationXML)
+	return f.context.Send(raw)
+}
+
+func (f *FiberContext) SendFile(file string, compress ...bool) error {
+	return f.context.SendFile(file, compress...)
+}
+
+func (f *FiberContext) SendStream(stream io.Reader, size ...int) error {
+	return f.context.SendStream(stream, size...)
+}
+
+func (f *FiberContext) Cookie(cookie *Cookie) {
+	f.context.Cookie(&fiber.Cookie{
+		Name:        cookie.Name,
+		Value:       cookie.Value,
+		Path:        cookie.Path,
+		Domain:      cookie.Domain,
+		MaxAge:      cookie.MaxAge,
+		Expires:     cookie.Expires,
+		Secure:      cookie.Secure,
+		HTTPOnly:    cookie.HTTPOnly,
+		SameSite:    cookie.SameSite,
+		SessionOnly: cookie.SessionOnly,
+	})
+}
+
+func (f *FiberContext) ClearCookie(key ...string) {
+	f.context.ClearCookie(key...)
+}
+
+func (f *FiberContext) Type(extension string, charset ...string) Context {
+	f.context.Type(extension, charset...)
+	return f
+}
+
+func (f *FiberContext) SendStatus(status int) error {
+	return f.context.SendStatus(status)
+}
+
+func (f *FiberContext) Render(name string, params map[string]string, layouts ...string) error {
+	var engine views.Engine
+	if app := fiberAppOf(f.context); app != nil {
+		engine = app.views
+	}
+	return render(f, engine, name, params, layouts)
+}
+
+func (f *FiberContext) Accepts(offers ...string) string {
+	return binding.Accepted(f.context.Get(fiber.HeaderAccept), offers...)
+}
+
+func (f *FiberContext) ClientCertificate() *x509.Certificate {
+	return VerifiedClientCertificate(f.context.Context().TLSConnectionState())
+}
+
+// SSE - the handler is run by the body stream writer after the handler of the route returned.
+// fasthttp doesn't report disconnected clients, so the stream is closed only when the next write fails,
+// e.g. by heartbeat, and Done isn't closed earlier.
+// Streams of apps not wrapped by NewFiberServer are not closed on shutdown.
+func (f *FiberContext) SSE(handler EventStreamHandler, heartbeat ...time.Duration) error {
+	streams := &eventStreams{}
+	if s := fiberAppOf(f.context); s != nil {
+		streams = &s.eventStreams
+	}
+	lastEventID := utils.CopyString(f.context.Get("Last-Event-ID"))
+	method, uri := utils.CopyString(f.context.Method()), utils.CopyString(f.context.OriginalURL())
+
+	response.SetEventStreamHeaders(f.Set)
+	f.context.Status(StatusOK)
+	f.context.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		// headers are sent before the first event
+		if w.Flush() != nil {
+			return
+		}
+		// the request context is released, so the panic can't reach the error handler and is logged like net/http does
+		err := streams.run(newEventStream(w, w.Flush, lastEventID), handler, heartbeatInterval(heartbeat), nil)
+		if err != nil {
+			stdlog.Printf("%s %s: %v", method, uri, err)
+		}
+	})
+	return nil
+}
+
 func newFiberContext(ctx *fiber.Ctx) *FiberContext {
 	return &FiberContext{
-		context: ctx,
-		request: newFiberRequest(ctx.Request()),
+		context:  ctx,
+		request:  newFiberRequest(ctx),
+		response: newFiberResponse(ctx.Response()),
 	}
 }
 
 // FiberRequest - wrapper on fiber fasthttp request
 type FiberRequest struct {
+	context *fiber.Ctx
 	request *fasthttp.Request
 }
 
@@ -177,49 +557,207 @@ func (f *FiberRequest) RequestURI() string {
 	return string(f.request.RequestURI())
 }
 
-func newFiberRequest(r *fasthttp.Request) Request {
-	return &FiberRequest{request: r}
+func (f *FiberRequest) Cookies(key string, defaultValue ...string) string {
+	return f.context.Cookies(key, defaultValue...)
+}
+
+func (f *FiberRequest) FormValue(key string, defaultValue ...string) string {
+	return f.context.FormValue(key, defaultValue...)
+}
+
+func (f *FiberRequest) MultipartForm() (*multipart.Form, error) {
+	return f.context.MultipartForm()
+}
+
+func (f *FiberRequest) FormFile(key string) (*multipart.FileHeader, error) {
+	return f.context.FormFile(key)
+}
+
+func (f *FiberRequest) Path() string {
+	return f.context.Path()
+}
+
+func (f *FiberRequest) QueryString() string {
+	return string(f.request.URI().QueryString())
+}
+
+func (f *FiberRequest) Scheme() string {
+	return f.context.Protocol()
+}
+
+func (f *FiberRequest) Protocol() string {
+	return string(f.request.Header.Protocol())
+}
+
+func (f *FiberRequest) VisitHeaders(visitor func(key, value string)) {
+	f.request.Header.VisitAll(func(key, value []byte) {
+		visitor(string(key), string(value))
+	})
+}
+
+func newFiberRequest(ctx *fiber.Ctx) Request {
+	return &FiberRequest{
+		context: ctx,
+		request: ctx.Request(),
+	}
+}
+
+// FiberResponse - wrapper on fiber fasthttp response
+type FiberResponse struct {
+	response *fasthttp.Response
+}
+
+func (f *FiberResponse) StatusCode() int {
+	return f.response.StatusCode()
+}
+
+func (f *FiberResponse) Body() []byte {
+	return f.response.Body()
+}
+
+func (f *FiberResponse) BodySize() int {
+	if f.response.IsBodyStream() {
+		return f.response.Header.ContentLength()
+	}
+	return len(f.response.Body())
+}
+
+func (f *FiberResponse) Header(key string) string {
+	return string(f.response.Header.Peek(key))
+}
+
+func (f *FiberResponse) VisitHeaders(visitor func(key, value string)) {
+	f.response.Header.VisitAll(func(key, value []byte) {
+		visitor(string(key), string(value))
+	})
+}
+
+func (f *FiberResponse) Reset() {
+	var keys []string
+	f.response.Header.VisitAll(func(key, _ []byte) {
+		keys = append(keys, string(key))
+	})
+	for _, key := range keys {
+		f.response.Header.Del(key)
+	}
+	f.response.SetStatusCode(StatusOK)
+	f.response.ResetBody()
+}
+
+func newFiberResponse(r *fasthttp.Response) Response {
+	return &FiberResponse{response: r}
 }
 
 type FiberGroup struct {
-	gr fiber.Router
+	gr     fiber.Router
+	routes *routeScope
 }
 
 func (fg *FiberGroup) Get(path string, handlers ...Handler) Router {
 	fg.gr.Get(path, FiberWrapHandlers(handlers...)...)
+	fg.routes.add(path, fiber.MethodGet)
 	return fg
 }
 
 func (fg *FiberGroup) Head(path string, handlers ...Handler) Router {
 	fg.gr.Head(path, FiberWrapHandlers(handlers...)...)
+	fg.routes.add(path, fiber.MethodHead)
 	return fg
 }
 
 func (fg *FiberGroup) Post(path string, handlers ...Handler) Router {
 	fg.gr.Post(path, FiberWrapHandlers(handlers...)...)
+	fg.routes.add(path, fiber.MethodPost)
 	return fg
 }
 
 func (fg *FiberGroup) Options(path string, handlers ...Handler) Router {
 	fg.gr.Options(path, FiberWrapHandlers(handlers...)...)
+	fg.routes.add(path, fiber.MethodOptions)
 	return fg
 }
 
 func (fg *FiberGroup) Delete(path string, handlers ...Handler) Router {
 	fg.gr.Delete(path, FiberWrapHandlers(handlers...)...)
+	fg.routes.add(path, fiber.MethodDelete)
+	return fg
+}
+
+func (fg *FiberGroup) Put(path string, handlers ...Handler) Router {
+	fg.gr.Put(path, FiberWrapHandlers(handlers...)...)
+	fg.routes.add(path, fiber.MethodPut)
+	return fg
+}
+
+func (fg *FiberGroup) Patch(path string, handlers ...Handler) Router {
+	fg.gr.Patch(path, FiberWrapHandlers(handlers...)...)
+	fg.routes.add(path, fiber.MethodPatch)
+	return fg
+}
+
+func (fg *FiberGroup) Connect(path string, handlers ...Handler) Router {
+	fg.gr.Connect(path, FiberWrapHandlers(handlers...)...)
+	fg.routes.add(path, fiber.MethodConnect)
+	return fg
+}
+
+func (fg *FiberGroup) Trace(path string, handlers ...Handler) Router {
+	fg.gr.Trace(path, FiberWrapHandlers(handlers...)...)
+	fg.routes.add(path, fiber.MethodTrace)
+	return fg
+}
+
+func (fg *FiberGroup) All(path string, handlers ...Handler) Router {
+	fg.gr.All(path, FiberWrapHandlers(handlers...)...)
+	fg.routes.add(path, allMethods...)
+	return fg
+}
+
+func (fg *FiberGroup) Add(method, path string, handlers ...Handler) Router {
+	fg.gr.Add(method, path, FiberWrapHandlers(handlers...)...)
+	fg.routes.add(path, strings.ToUpper(method))
 	return fg
 }
 
 func (fg *FiberGroup) Use(args ...interface{}) Router {
-	fg.gr.Use(args)
+	fg.gr.Use(fiberUseArgs(args)...)
+	return fg
+}
+
+func (fg *FiberGroup) WebSocket(path string, handler WebSocketHandler) Router {
+	fg.gr.Get(path, fiberWebSocketHandler(handler))
+	fg.routes.add(path, fiber.MethodGet)
 	return fg
 }
 
 func (fg *FiberGroup) Group(prefix string, handlers ...Handler) Router {
 	gr := fg.gr.Group(prefix, FiberWrapHandlers(handlers...)...)
-	return NewFiberGroup(gr)
+	return newFiberGroup(gr, fg.routes.table)
 }
 
+func (fg *FiberGroup) Name(name string) Router {
+	fg.routes.name(name)
+	return fg
+}
+
+func (fg *FiberGroup) Meta(key string, value interface{}) Router {
+	fg.routes.meta(key, value)
+	return fg
+}
+
+func (fg *FiberGroup) Routes() []Route {
+	return fg.routes.list()
+}
+
+// NewFiberGroup - return wrapper of Fiber group, Routes of the wrapper include routes registered through it only
 func NewFiberGroup(gr fiber.Router) *FiberGroup {
-	return &FiberGroup{gr: gr}
+	return newFiberGroup(gr, &routeTable{})
+}
+
+func newFiberGroup(gr fiber.Router, table *routeTable) *FiberGroup {
+	prefix := ""
+	if group, ok := gr.(*fiber.Group); ok {
+		prefix = group.Prefix
+	}
+	return &FiberGroup{gr: gr, routes: newRouteScope(table, prefix)}
 }
@@ -0,0 +1,74 @@
+// Package handoff - zero-downtime restarts of servers/http servers by passing the listening socket
+// to the new process, so connections are queued by the kernel instead of being refused during restart.
+//
+//	server := http.NewStdServer(nil)
+//	err := handoff.Serve(server, &handoff.Config{Addr: ":8080"})
+//
+// Serve listens from the socket inherited from systemd socket activation (LISTEN_FDS) or the restarting process.
+// On SIGUSR2 it starts the same executable with the same arguments and the socket, waits until the new process
+// starts to serve and shuts the server down, so active requests are drained by the old process.
+//
+// The new process is not a child of the service manager, so systemd units restarted by SIGUSR2
+// must allow it to become the main process, e.g. by Type=notify with NotifyAccess=all and sd_notify MAINPID.
+package handoff
+
+import (
+	"crypto/tls"
+	"github.com/ok93-01-18/go-ms-lib/log"
+	"time"
+)
+
+const (
+	// DefaultDrainTimeout - timeout of the server shutdown after the new process is ready
+	DefaultDrainTimeout = 30 * time.Second
+
+	// DefaultReadyTimeout - timeout of the new process startup, it is killed if it doesn't start to serve
+	DefaultReadyTimeout = 30 * time.Second
+)
+
+const (
+	// EnvListeners - number of listeners passed to the restarted process, descriptors start from 3
+	EnvListeners = "HANDOFF_LISTENERS"
+
+	// EnvReadyFD - descriptor of the pipe notified by the restarted process when it starts to serve
+	EnvReadyFD = "HANDOFF_READY_FD"
+
+	// systemd socket activation variables, see sd_listen_fds(3)
+	envListenPID     = "LISTEN_PID"
+	envListenFDs     = "LISTEN_FDS"
+	envListenFDNames = "LISTEN_FDNAMES"
+
+	// listenFDsStart - first descriptor of inherited listeners
+	listenFDsStart = 3
+)
+
+type Config struct {
+	// Addr - TCP address listened when no listener is inherited, e.g. ":8080"
+	Addr string
+
+	// TLS - config of TLS connections, e.g. http.CertReloader TLSConfig, connections are plain when nil
+	TLS *tls.Config
+
+	// DrainTimeout - timeout of the server shutdown after restart, DefaultDrainTimeout is used when zero
+	DrainTimeout time.Duration
+
+	// ReadyTimeout - timeout of the new process startup, DefaultReadyTimeout is used when zero
+	ReadyTimeout time.Duration
+
+	// Logger receives restart events and errors to the app channel, they are not logged when nil.
+	Logger log.Logger
+}
+
+func (c *Config) drainTimeout() time.Duration {
+	if c.DrainTimeout > 0 {
+		return c.DrainTimeout
+	}
+	return DefaultDrainTimeout
+}
+
+func (c *Config) readyTimeout() time.Duration {
+	if c.ReadyTimeout > 0 {
+		return c.ReadyTimeout
+	}
+	return DefaultReadyTimeout
+}
@@ -0,0 +1,290 @@
+//go:build !windows
+
+package handoff
+
+import (
+	"crypto/tls"
+	"fmt"
+	"github.com/ok93-01-18/go-ms-lib/log"
+	"github.com/ok93-01-18/go-ms-lib/servers/http"
+	"net"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+var (
+	inheritOnce sync.Once
+	inherited   []net.Listener
+	inheritErr  error
+
+	// readyPipe - pipe notified when the restarted process starts to serve, nil if it was not restarted
+	readyPipe *os.File
+	readyMu   sync.Mutex
+)
+
+// Listen returns the first inherited listener, the new TCP listener of the addr is returned if none is inherited.
+// Other inherited listeners stay open, they are returned by Listeners and passed on restart by Serve.
+func Listen(addr string) (net.Listener, error) {
+	listeners, err := Listeners()
+	if err != nil {
+		return nil, err
+	}
+	if len(listeners) > 0 {
+		return listeners[0], nil
+	}
+	return net.Listen("tcp", addr)
+}
+
+// Listeners returns listeners inherited from the restarting process or systemd socket activation
+// in order of descriptors. Inheritance variables are removed from the environment,
+// so they are not passed to child processes.
+func Listeners() ([]net.Listener, error) {
+	inheritOnce.Do(func() {
+		inherited, inheritErr = inherit()
+	})
+	return inherited, inheritErr
+}
+
+// Serve serves requests from the listener returned by Listen until the server is shut down
+// and restarts the process on SIGUSR2. If the new process fails to start, the server continues to serve.
+// After successful restart Serve returns when the server is drained.
+// All inherited listeners are passed to the new process in the same order, so those served by other servers
+// are inherited too, but only the first one is drained by Serve. Nil conf is treated as empty Config.
+func Serve(server http.Server, conf *Config) error {
+	if conf == nil {
+		conf = &Config{}
+	}
+
+	ln, err := Listen(conf.Addr)
+	if err != nil {
+		return err
+	}
+
+	// the served listener is passed first, as Listen of the new process returns it
+	passed, err := Listeners()
+	if err != nil {
+		return err
+	}
+	if len(passed) == 0 {
+		passed = []net.Listener{ln}
+	}
+
+	restarts := make(chan os.Signal, 1)
+	signal.Notify(restarts, syscall.SIGUSR2)
+	defer signal.Stop(restarts)
+
+	stop := make(chan struct{})
+	restarted := make(chan struct{})
+	drained := make(chan error, 1)
+	go func() {
+		for {
+			select {
+			case <-stop:
+				return
+			case <-restarts:
+			}
+
+			pid, err := restart(passed, conf.readyTimeout())
+			if err != nil {
+				if conf.Logger != nil {
+					conf.Logger.Errorf(log.TypeApp, "handoff: restart failed: %v", err)
+				}
+				continue
+			}
+
+			if conf.Logger != nil {
+				conf.Logger.Infof(log.TypeApp, "handoff: process %d is ready, draining connections", pid)
+			}
+			close(restarted)
+			drained <- server.ShutdownWithTimeout(conf.drainTimeout())
+			return
+		}
+	}()
+
+	server.OnStartup(notifyReady)
+
+	served := ln
+	if conf.TLS != nil {
+		served = tls.NewListener(ln, conf.TLS)
+	}
+	err = server.Listener(served)
+	close(stop)
+
+	select {
+	case <-restarted:
+		drainErr := <-drained
+		if err != nil {
+			return err
+		}
+		return drainErr
+	default:
+		return err
+	}
+}
+
+// restart starts the executable of the process with the listeners and waits until it is ready.
+func restart(listeners []net.Listener, timeout time.Duration) (int, error) {
+	files := make([]*os.File, 0, len(listeners)+1)
+	defer func() {
+		for _, file := range files {
+			_ = file.Close()
+		}
+	}()
+	for _, ln := range listeners {
+		filer, ok := ln.(interface{ File() (*os.File, error) })
+		if !ok {
+			return 0, fmt.Errorf("listener %T can't be passed", ln)
+		}
+		file, err := filer.File()
+		if err != nil {
+			return 0, err
+		}
+		files = append(files, file)
+	}
+
+	path, err := os.Executable()
+	if err != nil {
+		return 0, err
+	}
+
+	readyR, readyW, err := os.Pipe()
+	if err != nil {
+		return 0, err
+	}
+	defer readyR.Close()
+
+	cmd := exec.Command(path, os.Args[1:]...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.ExtraFiles = append(files, readyW)
+	cmd.Env = append(inheritanceFreeEnv(os.Environ()),
+		EnvListeners+"="+strconv.Itoa(len(listeners)),
+		EnvReadyFD+"="+strconv.Itoa(listenFDsStart+len(listeners)),
+	)
+
+	err = cmd.Start()
+	_ = readyW.Close()
+	if err != nil {
+		return 0, err
+	}
+	go func() {
+		_ = cmd.Wait()
+	}()
+
+	ready := make(chan bool, 1)
+	go func() {
+		n, _ := readyR.Read(make([]byte, 1))
+		ready <- n == 1
+	}()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case ok := <-ready:
+		if !ok {
+			return 0, fmt.Errorf("process %d exited before it was ready", cmd.Process.Pid)
+		}
+		return cmd.Process.Pid, nil
+	case <-timer.C:
+		_ = cmd.Process.Kill()
+		return 0, fmt.Errorf("process %d is not ready after %v", cmd.Process.Pid, timeout)
+	}
+}
+
+// notifyReady - startup hook notifying the restarting process, so it starts to drain
+func notifyReady() error {
+	readyMu.Lock()
+	defer readyMu.Unlock()
+
+	if readyPipe == nil {
+		return nil
+	}
+
+	_, err := readyPipe.Write([]byte{1})
+	_ = readyPipe.Close()
+	readyPipe = nil
+	return err
+}
+
+// inherit returns listeners passed by the restarting process or systemd and removes inheritance variables.
+func inherit() ([]net.Listener, error) {
+	defer func() {
+		for _, key := range []string{EnvListeners, EnvReadyFD, envListenPID, envListenFDs, envListenFDNames} {
+			_ = os.Unsetenv(key)
+		}
+	}()
+
+	var count string
+	var names []string
+	switch {
+	case os.Getenv(EnvListeners) != "":
+		count = os.Getenv(EnvListeners)
+
+		fd, err := strconv.Atoi(os.Getenv(EnvReadyFD))
+		if err == nil {
+			syscall.CloseOnExec(fd)
+			readyMu.Lock()
+			readyPipe = os.NewFile(uintptr(fd), "handoff-ready")
+			readyMu.Unlock()
+		}
+	case os.Getenv(envListenPID) == strconv.Itoa(os.Getpid()):
+		count = os.Getenv(envListenFDs)
+		names = strings.Split(os.Getenv(envListenFDNames), ":")
+	default:
+		return nil, nil
+	}
+
+	n, err := strconv.Atoi(count)
+	if err != nil || n < 0 {
+		return nil, fmt.Errorf("handoff: invalid number of listeners %q", count)
+	}
+
+	listeners := make([]net.Listener, 0, n)
+	for i := 0; i < n; i++ {
+		fd := listenFDsStart + i
+		name := "listener"
+		if i < len(names) && names[i] != "" {
+			name = names[i]
+		}
+
+		syscall.CloseOnExec(fd)
+		file := os.NewFile(uintptr(fd), name)
+		ln, err := net.FileListener(file)
+		_ = file.Close()
+		if err != nil {
+			for _, l := range listeners {
+				_ = l.Close()
+			}
+			return nil, fmt.Errorf("handoff: descriptor %d (%s): %w", fd, name, err)
+		}
+		listeners = append(listeners, ln)
+	}
+
+	return listeners, nil
+}
+
+// inheritanceFreeEnv returns the environment without inheritance variables.
+func inheritanceFreeEnv(env []string) []string {
+	clean := make([]string, 0, len(env))
+	for _, kv := range env {
+		key := kv
+		if i := strings.IndexByte(kv, '='); i >= 0 {
+			key = kv[:i]
+		}
+
+		switch key {
+		case EnvListeners, EnvReadyFD, envListenPID, envListenFDs, envListenFDNames:
+			continue
+		}
+		clean = append(clean, kv)
+	}
+	return clean
+}
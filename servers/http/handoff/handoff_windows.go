@@ -0,0 +1,37 @@
+//go:build windows
+
+package handoff
+
+import (
+	"crypto/tls"
+	"github.com/ok93-01-18/go-ms-lib/servers/http"
+	"net"
+)
+
+// Listen returns the new TCP listener of the addr, listeners are not inherited on Windows.
+func Listen(addr string) (net.Listener, error) {
+	return net.Listen("tcp", addr)
+}
+
+// Listeners returns no listeners, they are not inherited on Windows.
+func Listeners() ([]net.Listener, error) {
+	return nil, nil
+}
+
+// Serve serves requests from the listener returned by Listen until the server is shut down,
+// restarts are not supported on Windows. Nil conf is treated as empty Config.
+func Serve(server http.Server, conf *Config) error {
+	if conf == nil {
+		conf = &Config{}
+	}
+
+	ln, err := Listen(conf.Addr)
+	if err != nil {
+		return err
+	}
+
+	if conf.TLS != nil {
+		ln = tls.NewListener(ln, conf.TLS)
+	}
+	return server.Listener(ln)
+}
@@ -0,0 +1,68 @@
+package http
+
+import (
+	"context"
+	"sync"
+)
+
+// StartupHook - function called before the server starts to serve connections
+type StartupHook func() error
+
+// ShutdownHook - function called after the server stopped to serve connections
+type ShutdownHook func(context.Context) error
+
+// hooks - lifecycle hooks of the server
+type hooks struct {
+	mu       sync.Mutex
+	startup  []StartupHook
+	shutdown []ShutdownHook
+	shutDown bool
+}
+
+func (h *hooks) OnStartup(hooks ...StartupHook) {
+	h.mu.Lock()
+	h.startup = append(h.startup, hooks...)
+	h.mu.Unlock()
+}
+
+func (h *hooks) OnShutdown(hooks ...ShutdownHook) {
+	h.mu.Lock()
+	h.shutdown = append(h.shutdown, hooks...)
+	h.mu.Unlock()
+}
+
+// executeStartup runs startup hooks in registration order and stops on the first error.
+func (h *hooks) executeStartup() error {
+	h.mu.Lock()
+	startup := h.startup
+	h.mu.Unlock()
+
+	for _, hook := range startup {
+		err := hook()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// executeShutdown runs shutdown hooks once in registration order and returns the first error.
+func (h *hooks) executeShutdown(ctx context.Context) error {
+	h.mu.Lock()
+	if h.shutDown {
+		h.mu.Unlock()
+		return nil
+	}
+	h.shutDown = true
+	shutdown := h.shutdown
+	h.mu.Unlock()
+
+	var firstErr error
+	for _, hook := range shutdown {
+		err := hook(ctx)
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
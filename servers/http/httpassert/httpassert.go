@@ -0,0 +1,160 @@
+// Package httpassert - helpers of in-process handler tests based on Server.Test
+//
+//	func TestGetUser(t *testing.T) {
+//		t.Parallel()
+//		req := httpassert.NewRequest(http.MethodGet, "/users/1", nil)
+//		httpassert.Do(t, server, req).
+//			Status(http.StatusOK).
+//			Header("Content-Type", "application/json").
+//			JSON(`{"id":1,"name":"John"}`)
+//	}
+package httpassert
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/ok93-01-18/go-ms-lib/servers/http"
+	"io"
+	nethttp "net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// DefaultTimeout - timeout of requests made by Do
+const DefaultTimeout = 5 * time.Second
+
+// NewRequest returns request to the target path or URL.
+// String, []byte and io.Reader bodies are sent as is, other non-nil bodies are encoded as JSON with the content type.
+func NewRequest(method, target string, body interface{}) *nethttp.Request {
+	var reader io.Reader
+	isJSON := false
+	switch body := body.(type) {
+	case nil:
+	case string:
+		reader = strings.NewReader(body)
+	case []byte:
+		reader = bytes.NewReader(body)
+	case io.Reader:
+		reader = body
+	default:
+		raw, err := json.Marshal(body)
+		if err != nil {
+			panic("httpassert: can't encode body: " + err.Error())
+		}
+		reader = bytes.NewReader(raw)
+		isJSON = true
+	}
+
+	req := httptest.NewRequest(method, target, reader)
+	if isJSON {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	return req
+}
+
+// Response - response of the tested server with chainable assertions.
+// Failed assertions are reported by testing.TB.Errorf, so all of them are checked.
+// Header and Status fields shadowed by assertions are available through the embedded Response.
+type Response struct {
+	*nethttp.Response
+	t    testing.TB
+	body []byte
+}
+
+// Do makes the request to the server by Server.Test with DefaultTimeout and reads the response body.
+// The test is stopped if the request fails.
+func Do(t testing.TB, server http.Server, req *nethttp.Request) *Response {
+	t.Helper()
+
+	resp, err := server.Test(req, DefaultTimeout)
+	if err != nil {
+		t.Fatalf("httpassert: %s %s: %v", req.Method, req.URL, err)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		t.Fatalf("httpassert: %s %s: can't read body: %v", req.Method, req.URL, err)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	return &Response{Response: resp, t: t, body: body}
+}
+
+// BodyBytes returns the response body.
+func (r *Response) BodyBytes() []byte {
+	return r.body
+}
+
+// Status asserts the response status code.
+func (r *Response) Status(want int) *Response {
+	r.t.Helper()
+
+	if r.StatusCode != want {
+		r.t.Errorf("httpassert: status = %d, want %d, body: %s", r.StatusCode, want, r.body)
+	}
+	return r
+}
+
+// Header asserts the response header value, empty want asserts that the header is absent.
+func (r *Response) Header(key, want string) *Response {
+	r.t.Helper()
+
+	if got := r.Response.Header.Get(key); got != want {
+		r.t.Errorf("httpassert: header %s = %q, want %q", key, got, want)
+	}
+	return r
+}
+
+// Body asserts the response body.
+func (r *Response) Body(want string) *Response {
+	r.t.Helper()
+
+	if string(r.body) != want {
+		r.t.Errorf("httpassert: body = %q, want %q", r.body, want)
+	}
+	return r
+}
+
+// BodyContains asserts that the response body contains the substring.
+func (r *Response) BodyContains(substr string) *Response {
+	r.t.Helper()
+
+	if !strings.Contains(string(r.body), substr) {
+		r.t.Errorf("httpassert: body %q doesn't contain %q", r.body, substr)
+	}
+	return r
+}
+
+// JSON asserts that the response body is JSON equal to want regardless of formatting and key order.
+func (r *Response) JSON(want string) *Response {
+	r.t.Helper()
+
+	var got, expected interface{}
+	if err := json.Unmarshal(r.body, &got); err != nil {
+		r.t.Errorf("httpassert: body is not JSON: %v, body: %s", err, r.body)
+		return r
+	}
+	if err := json.Unmarshal([]byte(want), &expected); err != nil {
+		r.t.Errorf("httpassert: want is not JSON: %v", err)
+		return r
+	}
+
+	if !reflect.DeepEqual(got, expected) {
+		r.t.Errorf("httpassert: body = %s, want %s", r.body, want)
+	}
+	return r
+}
+
+// Decode decodes JSON body into out, the test is stopped if the body is not valid JSON.
+func (r *Response) Decode(out interface{}) *Response {
+	r.t.Helper()
+
+	if err := json.Unmarshal(r.body, out); err != nil {
+		r.t.Fatalf("httpassert: can't decode body: %v, body: %s", err, r.body)
+	}
+	return r
+}
@@ -0,0 +1,88 @@
+package httpassert_test
+
+import (
+	"fmt"
+	"github.com/ok93-01-18/go-ms-lib/servers/http"
+	"github.com/ok93-01-18/go-ms-lib/servers/http/httpassert"
+	"github.com/ok93-01-18/go-ms-lib/servers/http/internal/servertest"
+	"io"
+	nethttp "net/http"
+	"testing"
+)
+
+// recorder - testing.TB which records failures instead of failing the test
+type recorder struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func newServer(server http.Server) http.Server {
+	server.Post("/users", func(ctx http.Context) error {
+		var user struct {
+			Name string `json:"name"`
+		}
+		if err := ctx.BodyParser(&user); err != nil {
+			return err
+		}
+		ctx.Set("X-Name", user.Name)
+		return ctx.Status(http.StatusCreated).JSON(map[string]interface{}{"id": 1, "name": user.Name})
+	})
+	return server
+}
+
+func TestDo_Passed(t *testing.T) {
+	servertest.Run(t, func(t *testing.T, server http.Server) {
+		server = newServer(server)
+
+		rec := &recorder{TB: t}
+		var user struct {
+			ID   int    `json:"id"`
+			Name string `json:"name"`
+		}
+		resp := httpassert.Do(rec, server, httpassert.NewRequest(nethttp.MethodPost, "/users", map[string]string{"name": "alice"})).
+			Status(http.StatusCreated).
+			Header("X-Name", "alice").
+			Header("X-Missing", "").
+			BodyContains(`"alice"`).
+			JSON(`{"name": "alice", "id": 1}`).
+			Decode(&user)
+
+		if len(rec.errors) > 0 {
+			t.Errorf("assertions failed: %q", rec.errors)
+		}
+		if user.ID != 1 || user.Name != "alice" {
+			t.Errorf("Decode() = %+v", user)
+		}
+
+		// body remains readable through the embedded response
+		body, err := io.ReadAll(resp.Response.Body)
+		if err != nil || string(body) != string(resp.BodyBytes()) {
+			t.Errorf("Body = %q, %v, want %q", body, err, resp.BodyBytes())
+		}
+	})
+}
+
+func TestDo_Failed(t *testing.T) {
+	servertest.Run(t, func(t *testing.T, server http.Server) {
+		server = newServer(server)
+
+		rec := &recorder{TB: t}
+		httpassert.Do(rec, server, httpassert.NewRequest(nethttp.MethodPost, "/users", `{"name":"bob"}`)).
+			Status(http.StatusOK).
+			Header("X-Name", "alice").
+			Body("bob").
+			BodyContains("alice").
+			JSON(`{"name":"alice"}`)
+
+		// all failed assertions are reported, request body without content type isn't parsed
+		if len(rec.errors) != 5 {
+			t.Errorf("reported %d failures, want 5: %q", len(rec.errors), rec.errors)
+		}
+	})
+}
@@ -0,0 +1,542 @@
+package httptest
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"github.com/ok93-01-18/go-ms-lib/servers/http"
+	"github.com/ok93-01-18/go-ms-lib/servers/http/internal/binding"
+	"github.com/ok93-01-18/go-ms-lib/servers/http/internal/response"
+	"github.com/ok93-01-18/go-ms-lib/views"
+	"io"
+	"mime"
+	"net"
+	nethttp "net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// RenderCall - arguments of Context.Render call
+type RenderCall struct {
+	Name    string
+	Params  map[string]string
+	Layouts []string
+}
+
+// MockContext - implementation of http.Context which records the response, locals and Next calls.
+// Response is recorded as is, e.g. status is not changed for empty body.
+type MockContext struct {
+	request   *MockRequest
+	method    string
+	params    map[string]string
+	locals    map[string]interface{}
+	route     *http.Route
+	next      []http.Handler
+	nextCalls int
+	views     views.Engine
+	rendered  []RenderCall
+
+	events          []http.Event
+	disconnectAfter int
+
+	status  int
+	header  nethttp.Header
+	body    bytes.Buffer
+	cookies []*http.Cookie
+}
+
+// WithParam sets the route parameter.
+func (m *MockContext) WithParam(key, value string) *MockContext {
+	m.params[key] = value
+	return m
+}
+
+// WithLocal sets the value of Locals.
+func (m *MockContext) WithLocal(key string, value interface{}) *MockContext {
+	m.locals[key] = value
+	return m
+}
+
+// WithRoute sets path of the route returned by Route, the request path is used by default.
+func (m *MockContext) WithRoute(path string) *MockContext {
+	m.route = &http.Route{Method: m.method, Path: path}
+	return m
+}
+
+// WithNext sets handlers called by consecutive Next calls, Next returns nil when they are exhausted.
+func (m *MockContext) WithNext(handlers ...http.Handler) *MockContext {
+	m.next = append(m.next, handlers...)
+	return m
+}
+
+// WithViews sets the engine used by Render, Render only records calls without it.
+func (m *MockContext) WithViews(engine views.Engine) *MockContext {
+	m.views = engine
+	return m
+}
+
+// WithDisconnectAfter closes event streams after the number of sent events, as if the client disconnected.
+func (m *MockContext) WithDisconnectAfter(events int) *MockContext {
+	m.disconnectAfter = events
+	return m
+}
+
+// MockRequest returns the mocked request.
+func (m *MockContext) MockRequest() *MockRequest {
+	return m.request
+}
+
+// StatusCode returns the recorded response status.
+func (m *MockContext) StatusCode() int {
+	return m.status
+}
+
+// ResponseHeader returns the recorded response headers.
+func (m *MockContext) ResponseHeader() nethttp.Header {
+	return m.header
+}
+
+// BodyBytes returns the recorded response body.
+func (m *MockContext) BodyBytes() []byte {
+	return m.body.Bytes()
+}
+
+// BodyString returns the recorded response body.
+func (m *MockContext) BodyString() string {
+	return m.body.String()
+}
+
+// NextCalls returns the number of Next calls.
+func (m *MockContext) NextCalls() int {
+	return m.nextCalls
+}
+
+// RenderCalls returns arguments of Render calls.
+func (m *MockContext) RenderCalls() []RenderCall {
+	return m.rendered
+}
+
+// Events returns events sent by SSE handlers.
+func (m *MockContext) Events() []http.Event {
+	return m.events
+}
+
+// SetCookies returns cookies set by Cookie and ClearCookie.
+func (m *MockContext) SetCookies() []*http.Cookie {
+	return m.cookies
+}
+
+// AllLocals returns values set by Locals.
+func (m *MockContext) AllLocals() map[string]interface{} {
+	return m.locals
+}
+
+func (m *MockContext) IP() string {
+	host, _, err := net.SplitHostPort(m.request.request.RemoteAddr)
+	if err != nil {
+		return m.request.request.RemoteAddr
+	}
+	return host
+}
+
+func (m *MockContext) Hostname() string {
+	return m.request.request.Host
+}
+
+func (m *MockContext) Query(key string, defaultValue ...string) string {
+	return defaultString(m.request.request.URL.Query().Get(key), defaultValue)
+}
+
+func (m *MockContext) Set(key string, val string) {
+	m.header.Set(key, val)
+}
+
+func (m *MockContext) Append(field string, values ...string) {
+	for _, value := range values {
+		h := m.header.Get(field)
+		if h == "" {
+			m.header.Set(field, value)
+			continue
+		}
+
+		for _, v := range strings.Split(h, ",") {
+			if strings.TrimSpace(v) == value {
+				h = ""
+				break
+			}
+		}
+		if h != "" {
+			m.header.Set(field, h+", "+value)
+		}
+	}
+}
+
+func (m *MockContext) Write(p []byte) (int, error) {
+	return m.body.Write(p)
+}
+
+func (m *MockContext) Status(status int) http.Context {
+	m.status = status
+	return m
+}
+
+func (m *MockContext) GetReqHeaders() map[string]string {
+	headers := make(map[string]string, len(m.request.request.Header))
+	for key, values := range m.request.request.Header {
+		headers[key] = strings.Join(values, ", ")
+	}
+	return headers
+}
+
+func (m *MockContext) Request() http.Request {
+	return m.request
+}
+
+func (m *MockContext) Context() context.Context {
+	return m.request.request.Context()
+}
+
+func (m *MockContext) Response() http.Response {
+	return &MockResponse{context: m}
+}
+
+func (m *MockContext) Route() http.Route {
+	if m.route != nil {
+		return *m.route
+	}
+	return http.Route{Method: m.method, Path: m.request.Path()}
+}
+
+func (m *MockContext) Writef(f string, a ...interface{}) (int, error) {
+	return fmt.Fprintf(&m.body, f, a...)
+}
+
+func (m *MockContext) WriteString(s string) (int, error) {
+	return m.body.WriteString(s)
+}
+
+func (m *MockContext) BodyParser(out interface{}) error {
+	contentType, _, _ := mime.ParseMediaType(m.request.request.Header.Get("Content-Type"))
+
+	switch {
+	case strings.HasSuffix(contentType, "json"):
+		return json.Unmarshal(m.request.body, out)
+	case strings.HasSuffix(contentType, "xml"):
+		return xml.Unmarshal(m.request.body, out)
+	case contentType == "application/x-www-form-urlencoded", contentType == "multipart/form-data":
+		req := m.request.HTTPRequest()
+		err := req.ParseMultipartForm(multipartMemory)
+		if err != nil && err != nethttp.ErrNotMultipart {
+			return err
+		}
+		return binding.Decode(out, req.PostForm, "form", false)
+	}
+
+	return http.UnprocessableEntity()
+}
+
+func (m *MockContext) BindQuery(out interface{}) error {
+	return bindValues(out, m.request.request.URL.Query(), http.QueryTag)
+}
+
+func (m *MockContext) BindParams(out interface{}) error {
+	values := make(map[string][]string, len(m.params))
+	for key, value := range m.params {
+		values[key] = []string{value}
+	}
+	return bindValues(out, values, http.ParamsTag)
+}
+
+func (m *MockContext) BindHeaders(out interface{}) error {
+	return bindValues(out, m.request.request.Header, http.HeaderTag)
+}
+
+// bindValues binds values like http.Context.BindQuery does.
+func bindValues(out interface{}, values map[string][]string, tag string) error {
+	violations, err := binding.Bind(out, values, tag, tag == http.HeaderTag)
+	if err != nil {
+		return err
+	}
+	if len(violations) > 0 {
+		return http.UnprocessableEntity("validation failed").WithDetails(violations)
+	}
+	return nil
+}
+
+// Next - calls the next handler set by WithNext.
+func (m *MockContext) Next() error {
+	m.nextCalls++
+	if m.nextCalls > len(m.next) {
+		return nil
+	}
+	return m.next[m.nextCalls-1](m)
+}
+
+func (m *MockContext) Redirect(location string, status int) error {
+	m.Set("Location", location)
+	m.Status(status)
+	return nil
+}
+
+func (m *MockContext) Locals(key string, value ...interface{}) interface{} {
+	if len(value) == 0 {
+		return m.locals[key]
+	}
+
+	m.locals[key] = value[0]
+	return value[0]
+}
+
+func (m *MockContext) Get(key string, defaultValue ...string) string {
+	return defaultString(m.request.request.Header.Get(key), defaultValue)
+}
+
+func (m *MockContext) Method(override ...string) string {
+	if len(override) > 0 {
+		m.method = strings.ToUpper(override[0])
+	}
+	return m.method
+}
+
+func (m *MockContext) Params(key string, defaultValue ...string) string {
+	return defaultString(m.params[key], defaultValue)
+}
+
+func (m *MockContext) JSON(data interface{}) error {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	m.Set("Content-Type", "application/json")
+	m.body.Reset()
+	m.body.Write(raw)
+	return nil
+}
+
+func (m *MockContext) XML(data interface{}) error {
+	raw, err := xml.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	m.Set("Content-Type", "application/xml")
+	m.body.Reset()
+	m.body.Write(raw)
+	return nil
+}
+
+func (m *MockContext) SendFile(file string, _ ...bool) error {
+	raw, err := os.ReadFile(filepath.Clean(file))
+	if err != nil {
+		return http.NotFound(fmt.Sprintf("sendfile: file %s not found", file))
+	}
+
+	contentType := mime.TypeByExtension(filepath.Ext(file))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	m.Set("Content-Type", contentType)
+	m.body.Reset()
+	m.body.Write(raw)
+	return nil
+}
+
+// SendStream - the stream is read entirely into the recorded body.
+func (m *MockContext) SendStream(stream io.Reader, _ ...int) error {
+	m.body.Reset()
+	_, err := m.body.ReadFrom(stream)
+	return err
+}
+
+func (m *MockContext) Cookie(cookie *http.Cookie) {
+	c := *cookie
+	m.cookies = append(m.cookies, &c)
+	m.header.Add("Set-Cookie", response.StdCookie(cookie).String())
+}
+
+func (m *MockContext) ClearCookie(key ...string) {
+	if len(key) == 0 {
+		for _, cookie := range m.request.request.Cookies() {
+			key = append(key, cookie.Name)
+		}
+	}
+
+	for _, name := range key {
+		m.cookies = append(m.cookies, &http.Cookie{Name: name, Expires: response.CookieExpireDelete})
+		m.header.Add("Set-Cookie", response.ExpiredCookie(name).String())
+	}
+}
+
+func (m *MockContext) Type(extension string, charset ...string) http.Context {
+	if !strings.HasPrefix(extension, ".") {
+		extension = "." + extension
+	}
+	contentType := mime.TypeByExtension(extension)
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	if i := strings.IndexByte(contentType, ';'); i >= 0 {
+		contentType = contentType[:i]
+	}
+	if len(charset) > 0 {
+		contentType += "; charset=" + charset[0]
+	}
+
+	m.Set("Content-Type", contentType)
+	return m
+}
+
+func (m *MockContext) SendStatus(status int) error {
+	m.Status(status)
+
+	if m.body.Len() == 0 {
+		_, _ = m.WriteString(nethttp.StatusText(status))
+	}
+	return nil
+}
+
+// Render - the call is recorded, the template is rendered like servers do if the views engine is set.
+func (m *MockContext) Render(name string, params map[string]string, layouts ...string) error {
+	m.rendered = append(m.rendered, RenderCall{Name: name, Params: params, Layouts: layouts})
+	if m.views == nil {
+		return nil
+	}
+
+	body, err := response.Render(m.views, name, params, layouts)
+	if err != nil {
+		return http.InternalServerError().Wrap(err)
+	}
+
+	m.Set("Content-Type", response.HTMLContentType)
+	_, err = m.WriteString(body)
+	return err
+}
+
+func (m *MockContext) Accepts(offers ...string) string {
+	return binding.Accepted(m.request.request.Header.Get("Accept"), offers...)
+}
+
+func (m *MockContext) ClientCertificate() *x509.Certificate {
+	return http.VerifiedClientCertificate(m.request.request.TLS)
+}
+
+// SSE - the handler is run before SSE returns without heartbeats, events are recorded and written to the body.
+func (m *MockContext) SSE(handler http.EventStreamHandler, _ ...time.Duration) error {
+	response.SetEventStreamHeaders(m.Set)
+	m.Status(http.StatusOK)
+
+	stream := &mockEventStream{context: m, done: make(chan struct{})}
+	_ = handler(stream)
+	stream.close()
+	return nil
+}
+
+// mockEventStream - EventStream recording events of MockContext
+type mockEventStream struct {
+	context *MockContext
+	sent    int
+	done    chan struct{}
+	closed  bool
+}
+
+func (s *mockEventStream) Send(event http.Event) error {
+	if s.closed {
+		return http.ErrStreamClosed
+	}
+
+	_, err := event.WriteTo(&s.context.body)
+	if err != nil {
+		return err
+	}
+	s.context.events = append(s.context.events, event)
+
+	s.sent++
+	if s.context.disconnectAfter > 0 && s.sent >= s.context.disconnectAfter {
+		s.close()
+	}
+	return nil
+}
+
+func (s *mockEventStream) Comment(comment string) error {
+	if s.closed {
+		return http.ErrStreamClosed
+	}
+
+	for _, line := range strings.Split(comment, "\n") {
+		s.context.body.WriteString(":" + line + "\n")
+	}
+	s.context.body.WriteByte('\n')
+	return nil
+}
+
+func (s *mockEventStream) LastEventID() string {
+	return s.context.Get("Last-Event-ID")
+}
+
+func (s *mockEventStream) Done() <-chan struct{} {
+	return s.done
+}
+
+func (s *mockEventStream) close() {
+	if !s.closed {
+		s.closed = true
+		close(s.done)
+	}
+}
+
+// NewContext - return MockContext of the request with StatusOK response
+func NewContext(req *MockRequest) *MockContext {
+	return &MockContext{
+		request: req,
+		method:  req.request.Method,
+		params:  make(map[string]string),
+		locals:  make(map[string]interface{}),
+		status:  http.StatusOK,
+		header:  make(nethttp.Header),
+	}
+}
+
+// MockResponse - implementation of http.Response of the recorded response
+type MockResponse struct {
+	context *MockContext
+}
+
+func (r *MockResponse) StatusCode() int {
+	return r.context.status
+}
+
+func (r *MockResponse) Body() []byte {
+	return r.context.body.Bytes()
+}
+
+func (r *MockResponse) BodySize() int {
+	return r.context.body.Len()
+}
+
+func (r *MockResponse) Header(key string) string {
+	return r.context.header.Get(key)
+}
+
+func (r *MockResponse) VisitHeaders(visitor func(key, value string)) {
+	visitHeaders(r.context.header, visitor)
+}
+
+func (r *MockResponse) Reset() {
+	r.context.status = http.StatusOK
+	r.context.header = make(nethttp.Header)
+	r.context.body.Reset()
+	r.context.cookies = nil
+}
+
+// compile-time checks of interface implementations
+var (
+	_ http.Context  = (*MockContext)(nil)
+	_ http.Request  = (*MockRequest)(nil)
+	_ http.Response = (*MockResponse)(nil)
+)
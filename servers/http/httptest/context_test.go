@@ -0,0 +1,206 @@
+package httptest_test
+
+import (
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"github.com/ok93-01-18/go-ms-lib/servers/http"
+	"github.com/ok93-01-18/go-ms-lib/servers/http/httptest"
+	nethttp "net/http"
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+type user struct {
+	Name string `json:"name" form:"name" validate:"required"`
+	Age  int    `json:"age" form:"age" validate:"min=18"`
+}
+
+func violations(t *testing.T, err error) []http.FieldViolation {
+	t.Helper()
+
+	httpErr := http.AsHTTPError(err)
+	if httpErr.Status != http.StatusUnprocessableEntity {
+		t.Fatalf("error status = %d, want %d", httpErr.Status, http.StatusUnprocessableEntity)
+	}
+	details, ok := httpErr.Details.([]http.FieldViolation)
+	if !ok {
+		t.Fatalf("error details = %#v, want []FieldViolation", httpErr.Details)
+	}
+	return details
+}
+
+func TestMockContext_Response(t *testing.T) {
+	ctx := httptest.NewContext(httptest.NewRequest(nethttp.MethodGet, "/"))
+
+	err := ctx.Status(http.StatusCreated).JSON(user{Name: "alice", Age: 20})
+	if err != nil {
+		t.Fatalf("JSON() = %v", err)
+	}
+	ctx.Cookie(&http.Cookie{Name: "session", Value: "1", HTTPOnly: true})
+	ctx.Cookie(&http.Cookie{Name: "theme", Value: "dark", SameSite: http.CookieSameSiteStrictMode})
+
+	if ctx.StatusCode() != http.StatusCreated {
+		t.Errorf("StatusCode() = %d, want %d", ctx.StatusCode(), http.StatusCreated)
+	}
+	if got := ctx.ResponseHeader().Get("Content-Type"); got != "application/json" {
+		t.Errorf("Content-Type = %q, want application/json", got)
+	}
+	if got := ctx.BodyString(); got != `{"name":"alice","age":20}` {
+		t.Errorf("BodyString() = %q", got)
+	}
+	// cookies are written like servers do, lax mode is used when SameSite is empty
+	want := []string{"session=1; HttpOnly; SameSite=Lax", "theme=dark; SameSite=Strict"}
+	if got := ctx.ResponseHeader().Values("Set-Cookie"); !reflect.DeepEqual(got, want) {
+		t.Errorf("Set-Cookie = %q, want %q", got, want)
+	}
+	if len(ctx.SetCookies()) != 2 || ctx.SetCookies()[0].Name != "session" {
+		t.Errorf("SetCookies() = %+v", ctx.SetCookies())
+	}
+}
+
+func TestMockContext_Next(t *testing.T) {
+	errNext := errors.New("next")
+	ctx := httptest.NewContext(httptest.NewRequest(nethttp.MethodGet, "/")).
+		WithNext(func(ctx http.Context) error {
+			ctx.Locals("user", "alice")
+			return ctx.Next()
+		}, func(ctx http.Context) error {
+			return errNext
+		})
+
+	if err := ctx.Next(); err != errNext {
+		t.Errorf("Next() = %v, want %v", err, errNext)
+	}
+	if err := ctx.Next(); err != nil {
+		t.Errorf("Next() after handlers = %v, want nil", err)
+	}
+	if ctx.NextCalls() != 3 {
+		t.Errorf("NextCalls() = %d, want 3", ctx.NextCalls())
+	}
+	if ctx.Locals("user") != "alice" {
+		t.Errorf("Locals(user) = %v, want alice", ctx.Locals("user"))
+	}
+}
+
+func TestMockContext_BodyParser(t *testing.T) {
+	var fromJSON user
+	req := httptest.NewRequest(nethttp.MethodPost, "/").WithJSON(user{Name: "alice", Age: 20})
+	if err := httptest.NewContext(req).BodyParser(&fromJSON); err != nil {
+		t.Fatalf("BodyParser(json) = %v", err)
+	}
+	if fromJSON != (user{Name: "alice", Age: 20}) {
+		t.Errorf("BodyParser(json) = %+v", fromJSON)
+	}
+
+	var fromForm user
+	req = httptest.NewRequest(nethttp.MethodPost, "/").WithForm(url.Values{"name": {"bob"}, "age": {"30"}})
+	if err := httptest.NewContext(req).BodyParser(&fromForm); err != nil {
+		t.Fatalf("BodyParser(form) = %v", err)
+	}
+	if fromForm != (user{Name: "bob", Age: 30}) {
+		t.Errorf("BodyParser(form) = %+v", fromForm)
+	}
+}
+
+func TestMockContext_Bind(t *testing.T) {
+	type query struct {
+		Page int      `query:"page" validate:"min=1"`
+		Tags []string `query:"tag"`
+	}
+
+	var q query
+	req := httptest.NewRequest(nethttp.MethodGet, "/?page=2&tag=a&tag=b")
+	if err := httptest.NewContext(req).BindQuery(&q); err != nil {
+		t.Fatalf("BindQuery() = %v", err)
+	}
+	if !reflect.DeepEqual(q, query{Page: 2, Tags: []string{"a", "b"}}) {
+		t.Errorf("BindQuery() = %+v", q)
+	}
+
+	err := httptest.NewContext(httptest.NewRequest(nethttp.MethodGet, "/?page=0")).BindQuery(&query{})
+	want := []http.FieldViolation{{Field: "page", Rule: "min", Message: "must be at least 1"}}
+	if got := violations(t, err); !reflect.DeepEqual(got, want) {
+		t.Errorf("BindQuery() violations = %+v, want %+v", got, want)
+	}
+
+	type params struct {
+		ID int `params:"id"`
+	}
+	err = httptest.NewContext(httptest.NewRequest(nethttp.MethodGet, "/")).WithParam("id", "x").BindParams(&params{})
+	want = []http.FieldViolation{{Field: "id", Rule: "type", Message: "invalid value: invalid syntax"}}
+	if got := violations(t, err); !reflect.DeepEqual(got, want) {
+		t.Errorf("BindParams() violations = %+v, want %+v", got, want)
+	}
+
+	type headers struct {
+		RequestID string `header:"x-request-id" validate:"required"`
+	}
+	var h headers
+	req = httptest.NewRequest(nethttp.MethodGet, "/").WithHeader("X-Request-ID", "abc")
+	if err := httptest.NewContext(req).BindHeaders(&h); err != nil {
+		t.Fatalf("BindHeaders() = %v", err)
+	}
+	if h.RequestID != "abc" {
+		t.Errorf("BindHeaders() = %+v", h)
+	}
+}
+
+func TestMockContext_Accepts(t *testing.T) {
+	for _, test := range []struct {
+		accept string
+		want   string
+	}{
+		{accept: "", want: "json"},
+		{accept: "text/html", want: "html"},
+		{accept: "text/*;q=0.5, application/json", want: "json"},
+		{accept: "image/png", want: ""},
+	} {
+		req := httptest.NewRequest(nethttp.MethodGet, "/").WithHeader("Accept", test.accept)
+		if got := httptest.NewContext(req).Accepts("json", "html"); got != test.want {
+			t.Errorf("Accepts() with Accept %q = %q, want %q", test.accept, got, test.want)
+		}
+	}
+}
+
+func TestMockContext_SSE(t *testing.T) {
+	ctx := httptest.NewContext(httptest.NewRequest(nethttp.MethodGet, "/")).WithDisconnectAfter(2)
+
+	var sendErr error
+	err := ctx.SSE(func(stream http.EventStream) error {
+		for i := 0; i < 3 && sendErr == nil; i++ {
+			sendErr = stream.Send(http.Event{Data: "tick"})
+		}
+		<-stream.Done()
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("SSE() = %v", err)
+	}
+
+	if sendErr != http.ErrStreamClosed {
+		t.Errorf("Send() after disconnect = %v, want %v", sendErr, http.ErrStreamClosed)
+	}
+	if len(ctx.Events()) != 2 {
+		t.Errorf("Events() = %+v, want 2 events", ctx.Events())
+	}
+	if got := ctx.ResponseHeader().Get("Content-Type"); got != "text/event-stream" {
+		t.Errorf("Content-Type = %q, want text/event-stream", got)
+	}
+	if got := ctx.BodyString(); got != "data: tick\n\ndata: tick\n\n" {
+		t.Errorf("BodyString() = %q", got)
+	}
+}
+
+func TestMockContext_ClientCertificate(t *testing.T) {
+	cert := &x509.Certificate{Subject: pkix.Name{CommonName: "client"}}
+
+	ctx := httptest.NewContext(httptest.NewRequest(nethttp.MethodGet, "/").WithClientCertificate(cert))
+	if ctx.ClientCertificate() != cert {
+		t.Errorf("ClientCertificate() = %v, want %v", ctx.ClientCertificate(), cert)
+	}
+	if ctx := httptest.NewContext(httptest.NewRequest(nethttp.MethodGet, "/")); ctx.ClientCertificate() != nil {
+		t.Errorf("ClientCertificate() without TLS = %v, want nil", ctx.ClientCertificate())
+	}
+}
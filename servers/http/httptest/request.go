@@ -0,0 +1,255 @@
+// Package httptest - mocks of servers/http Context, Request and Response for handler unit tests
+//
+//	req := httptest.NewRequest(http.MethodPost, "/users?notify=true").WithJSON(user)
+//	ctx := httptest.NewContext(req).WithParam("id", "1")
+//	err := handler(ctx)
+//	// check ctx.StatusCode(), ctx.ResponseHeader(), ctx.BodyString(), ctx.NextCalls()
+package httptest
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"io"
+	"mime/multipart"
+	nethttp "net/http"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// multipartMemory - max memory of parsed multipart forms
+const multipartMemory = 32 << 20
+
+// MockRequest - implementation of http.Request built from method, target and builders
+type MockRequest struct {
+	request *nethttp.Request
+	body    []byte
+}
+
+// WithHeader adds the request header value.
+func (r *MockRequest) WithHeader(key, value string) *MockRequest {
+	r.request.Header.Add(key, value)
+	return r
+}
+
+// WithQuery adds the query string parameter value.
+func (r *MockRequest) WithQuery(key, value string) *MockRequest {
+	query := r.request.URL.Query()
+	query.Add(key, value)
+	r.request.URL.RawQuery = query.Encode()
+	r.request.RequestURI = r.request.URL.RequestURI()
+	r.resetForm()
+	return r
+}
+
+// WithCookie adds the request cookie.
+func (r *MockRequest) WithCookie(name, value string) *MockRequest {
+	r.request.AddCookie(&nethttp.Cookie{Name: name, Value: value})
+	return r
+}
+
+// WithBody sets the request body and its content type, content type is not changed if it is empty.
+func (r *MockRequest) WithBody(body []byte, contentType string) *MockRequest {
+	r.body = body
+	r.request.ContentLength = int64(len(body))
+	if contentType != "" {
+		r.request.Header.Set("Content-Type", contentType)
+	}
+	r.resetForm()
+	return r
+}
+
+// resetForm drops parsed forms, so they are parsed again from the changed request.
+func (r *MockRequest) resetForm() {
+	r.request.Form = nil
+	r.request.PostForm = nil
+	r.request.MultipartForm = nil
+}
+
+// WithJSON sets the request body encoded as JSON, it panics if the value can't be encoded.
+func (r *MockRequest) WithJSON(value interface{}) *MockRequest {
+	body, err := json.Marshal(value)
+	if err != nil {
+		panic("httptest: can't encode JSON body: " + err.Error())
+	}
+	return r.WithBody(body, "application/json")
+}
+
+// WithForm sets the request body encoded as application/x-www-form-urlencoded.
+func (r *MockRequest) WithForm(values url.Values) *MockRequest {
+	return r.WithBody([]byte(values.Encode()), "application/x-www-form-urlencoded")
+}
+
+// WithMultipartForm sets the request body encoded as multipart/form-data,
+// files are given as field name to file name to content.
+func (r *MockRequest) WithMultipartForm(values url.Values, files map[string]map[string][]byte) *MockRequest {
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	for key, vals := range values {
+		for _, val := range vals {
+			_ = w.WriteField(key, val)
+		}
+	}
+	for field, named := range files {
+		for name, content := range named {
+			part, _ := w.CreateFormFile(field, name)
+			_, _ = part.Write(content)
+		}
+	}
+	_ = w.Close()
+
+	return r.WithBody(buf.Bytes(), w.FormDataContentType())
+}
+
+// WithClientCertificate makes the request HTTPS with the verified client certificate.
+func (r *MockRequest) WithClientCertificate(cert *x509.Certificate) *MockRequest {
+	r.request.URL.Scheme = "https"
+	r.request.TLS = &tls.ConnectionState{
+		Version:           tls.VersionTLS13,
+		HandshakeComplete: true,
+		PeerCertificates:  []*x509.Certificate{cert},
+		VerifiedChains:    [][]*x509.Certificate{{cert}},
+	}
+	return r
+}
+
+// WithContext sets the context returned by Context.Context.
+func (r *MockRequest) WithContext(ctx context.Context) *MockRequest {
+	r.request = r.request.WithContext(ctx)
+	return r
+}
+
+// WithRemoteAddr sets the remote address of the client, e.g. "10.0.0.1:1234".
+func (r *MockRequest) WithRemoteAddr(addr string) *MockRequest {
+	r.request.RemoteAddr = addr
+	return r
+}
+
+// HTTPRequest returns the underlying net/http request with the body.
+func (r *MockRequest) HTTPRequest() *nethttp.Request {
+	r.request.Body = io.NopCloser(bytes.NewReader(r.body))
+	return r.request
+}
+
+func (r *MockRequest) GetContentLength() int {
+	return len(r.body)
+}
+
+func (r *MockRequest) Body() []byte {
+	return r.body
+}
+
+func (r *MockRequest) RequestURI() string {
+	return r.request.URL.RequestURI()
+}
+
+func (r *MockRequest) Cookies(key string, defaultValue ...string) string {
+	cookie, err := r.request.Cookie(key)
+	if err != nil || cookie.Value == "" {
+		return defaultString("", defaultValue)
+	}
+	return cookie.Value
+}
+
+func (r *MockRequest) FormValue(key string, defaultValue ...string) string {
+	req := r.HTTPRequest()
+	_ = req.ParseMultipartForm(multipartMemory)
+	return defaultString(req.FormValue(key), defaultValue)
+}
+
+func (r *MockRequest) MultipartForm() (*multipart.Form, error) {
+	req := r.HTTPRequest()
+	err := req.ParseMultipartForm(multipartMemory)
+	if err != nil {
+		return nil, err
+	}
+	return req.MultipartForm, nil
+}
+
+func (r *MockRequest) FormFile(key string) (*multipart.FileHeader, error) {
+	form, err := r.MultipartForm()
+	if err != nil {
+		return nil, err
+	}
+
+	files := form.File[key]
+	if len(files) == 0 {
+		return nil, nethttp.ErrMissingFile
+	}
+	return files[0], nil
+}
+
+func (r *MockRequest) Path() string {
+	return r.request.URL.Path
+}
+
+func (r *MockRequest) QueryString() string {
+	return r.request.URL.RawQuery
+}
+
+func (r *MockRequest) Scheme() string {
+	if r.request.URL.Scheme == "https" {
+		return "https"
+	}
+	return "http"
+}
+
+func (r *MockRequest) Protocol() string {
+	return r.request.Proto
+}
+
+func (r *MockRequest) VisitHeaders(visitor func(key, value string)) {
+	visitHeaders(r.request.Header, visitor)
+}
+
+// NewRequest - return MockRequest to the target path or URL, it panics if the target is invalid
+func NewRequest(method, target string) *MockRequest {
+	u, err := url.Parse(target)
+	if err != nil {
+		panic("httptest: invalid target: " + err.Error())
+	}
+
+	host := u.Host
+	if host == "" {
+		host = "example.com"
+	}
+
+	return &MockRequest{
+		request: &nethttp.Request{
+			Method:     strings.ToUpper(method),
+			URL:        u,
+			RequestURI: u.RequestURI(),
+			Proto:      "HTTP/1.1",
+			ProtoMajor: 1,
+			ProtoMinor: 1,
+			Header:     make(nethttp.Header),
+			Host:       host,
+			RemoteAddr: "192.0.2.1:1234",
+		},
+	}
+}
+
+// visitHeaders visits headers sorted by key.
+func visitHeaders(header nethttp.Header, visitor func(key, value string)) {
+	keys := make([]string, 0, len(header))
+	for key := range header {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		for _, value := range header[key] {
+			visitor(key, value)
+		}
+	}
+}
+
+func defaultString(value string, defaultValue []string) string {
+	if value == "" && len(defaultValue) > 0 {
+		return defaultValue[0]
+	}
+	return value
+}
@@ -0,0 +1,97 @@
+package binding
+
+import (
+	"mime"
+	"strconv"
+	"strings"
+)
+
+// acceptRange - media range of Accept header
+type acceptRange struct {
+	typ     string
+	subtype string
+	q       float64
+}
+
+// Accepted returns the offer which is the best for the Accept header value like http.Context.Accepts does.
+func Accepted(header string, offers ...string) string {
+	if len(offers) == 0 {
+		return ""
+	}
+	if strings.TrimSpace(header) == "" {
+		return offers[0]
+	}
+
+	ranges := parseAccept(header)
+
+	best, bestQ := "", 0.0
+	for _, offer := range offers {
+		typ, subtype := splitMediaType(offerMediaType(offer))
+
+		// quality of the offer is defined by the most specific matching range
+		q, specificity := 0.0, -1
+		for _, r := range ranges {
+			s := 0
+			switch {
+			case r.typ == typ && r.subtype == subtype:
+				s = 2
+			case r.typ == typ && r.subtype == "*":
+				s = 1
+			case r.typ == "*" && r.subtype == "*":
+				s = 0
+			default:
+				continue
+			}
+			if s > specificity {
+				q, specificity = r.q, s
+			}
+		}
+
+		if q > bestQ {
+			best, bestQ = offer, q
+		}
+	}
+	return best
+}
+
+func parseAccept(header string) []acceptRange {
+	var ranges []acceptRange
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(part, ";")
+		typ, subtype := splitMediaType(strings.TrimSpace(fields[0]))
+		if typ == "" {
+			continue
+		}
+
+		q := 1.0
+		for _, param := range fields[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				v, err := strconv.ParseFloat(param[2:], 64)
+				if err == nil {
+					q = v
+				}
+			}
+		}
+
+		ranges = append(ranges, acceptRange{typ: typ, subtype: subtype, q: q})
+	}
+	return ranges
+}
+
+// offerMediaType returns media type of the offer, which may be a file extension.
+func offerMediaType(offer string) string {
+	if !strings.Contains(offer, "/") {
+		offer = mime.TypeByExtension("." + offer)
+	}
+	mediaType, _, _ := mime.ParseMediaType(offer)
+	return mediaType
+}
+
+func splitMediaType(mediaType string) (string, string) {
+	i := strings.IndexByte(mediaType, '/')
+	if i < 0 {
+		return "", ""
+	}
+	return strings.ToLower(mediaType[:i]), strings.ToLower(mediaType[i+1:])
+}
@@ -0,0 +1,36 @@
+package binding
+
+import (
+	"errors"
+	"strconv"
+)
+
+// Bind decodes values into the struct pointed by out like Decode and validates it like Validate.
+// Values which can't be decoded are returned as violations of "type" rule, the struct is not validated then.
+func Bind(out interface{}, values map[string][]string, tag string, fold bool) ([]FieldViolation, error) {
+	err := Decode(out, values, tag, fold)
+
+	var decodeErrs DecodeErrors
+	if errors.As(err, &decodeErrs) {
+		violations := make([]FieldViolation, 0, len(decodeErrs))
+		for _, decodeErr := range decodeErrs {
+			cause := decodeErr.Err
+			var numErr *strconv.NumError
+			if errors.As(cause, &numErr) {
+				cause = numErr.Err
+			}
+
+			violations = append(violations, FieldViolation{
+				Field:   decodeErr.Name,
+				Rule:    "type",
+				Message: "invalid value: " + cause.Error(),
+			})
+		}
+		return violations, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return Validate(out, tag)
+}
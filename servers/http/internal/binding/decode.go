@@ -0,0 +1,209 @@
+package binding
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	timeType            = reflect.TypeOf(time.Time{})
+	durationType        = reflect.TypeOf(time.Duration(0))
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// timeLayouts - layouts of time values, tried in order
+var timeLayouts = []string{time.RFC3339Nano, "2006-01-02T15:04:05", "2006-01-02"}
+
+// DecodeError - error of decoding the value of the field
+type DecodeError struct {
+	// Name - name of the value, i.e. the tag value or the field name
+	Name  string
+	Field string
+	Err   error
+}
+
+func (e *DecodeError) Error() string {
+	return fmt.Sprintf("decode: field %s: %v", e.Field, e.Err)
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// DecodeErrors - errors of all fields which values can't be decoded
+type DecodeErrors []*DecodeError
+
+func (e DecodeErrors) Error() string {
+	messages := make([]string, 0, len(e))
+	for _, err := range e {
+		messages = append(messages, err.Error())
+	}
+	return strings.Join(messages, "; ")
+}
+
+// Decode fills exported fields of the struct pointed by out from values.
+// Field is looked up by the given tag or, when the tag is absent, by the case-insensitive field name.
+// Tagged names are case-insensitive too if fold is set, e.g. for headers.
+// Fields which values can't be decoded are returned as DecodeErrors after all fields are decoded.
+func Decode(out interface{}, values map[string][]string, tag string, fold bool) error {
+	rv := reflect.ValueOf(out)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return errors.New("decode: out must be a non-nil pointer to struct")
+	}
+
+	var errs DecodeErrors
+	rv = rv.Elem()
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+
+		name := field.Tag.Get(tag)
+		if name == "-" {
+			continue
+		}
+
+		vals, ok := lookupValues(values, name, field.Name, fold)
+		if !ok || len(vals) == 0 {
+			continue
+		}
+
+		err := setField(rv.Field(i), vals)
+		if err != nil {
+			if name == "" {
+				name = field.Name
+			}
+			errs = append(errs, &DecodeError{Name: name, Field: field.Name, Err: err})
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+func lookupValues(values map[string][]string, name, fieldName string, fold bool) ([]string, bool) {
+	if name != "" {
+		vals, ok := values[name]
+		if ok || !fold {
+			return vals, ok
+		}
+		fieldName = name
+	}
+
+	for key, vals := range values {
+		if strings.EqualFold(key, fieldName) {
+			return vals, true
+		}
+	}
+	return nil, false
+}
+
+func setField(field reflect.Value, vals []string) error {
+	if field.Kind() == reflect.Ptr {
+		if field.IsNil() {
+			field.Set(reflect.New(field.Type().Elem()))
+		}
+		return setField(field.Elem(), vals)
+	}
+
+	if field.Kind() == reflect.Slice && !isTextUnmarshaler(field) {
+		// single value is treated as comma-separated list, e.g. ?ids=1,2,3
+		if len(vals) == 1 && strings.Contains(vals[0], ",") {
+			vals = strings.Split(vals[0], ",")
+		}
+
+		slice := reflect.MakeSlice(field.Type(), 0, len(vals))
+		for _, val := range vals {
+			item := reflect.New(field.Type().Elem()).Elem()
+			err := setField(item, []string{strings.TrimSpace(val)})
+			if err != nil {
+				return err
+			}
+			slice = reflect.Append(slice, item)
+		}
+		field.Set(slice)
+		return nil
+	}
+
+	return setValue(field, vals[0])
+}
+
+func isTextUnmarshaler(field reflect.Value) bool {
+	return field.CanAddr() && field.Addr().Type().Implements(textUnmarshalerType)
+}
+
+func setValue(field reflect.Value, val string) error {
+	switch field.Type() {
+	case timeType:
+		t, err := parseTime(val)
+		if err != nil {
+			return err
+		}
+		field.Set(reflect.ValueOf(t))
+		return nil
+	case durationType:
+		d, err := time.ParseDuration(val)
+		if err != nil {
+			return err
+		}
+		field.SetInt(int64(d))
+		return nil
+	}
+
+	if isTextUnmarshaler(field) {
+		return field.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(val))
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(val)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(val)
+		if err != nil {
+			return err
+		}
+		field.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(val, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(val, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(val, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetFloat(n)
+	default:
+		return fmt.Errorf("unsupported type %s", field.Type())
+	}
+	return nil
+}
+
+func parseTime(val string) (time.Time, error) {
+	var err error
+	for _, layout := range timeLayouts {
+		var t time.Time
+		t, err = time.Parse(layout, val)
+		if err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, err
+}
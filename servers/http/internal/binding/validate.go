@@ -0,0 +1,233 @@
+package binding
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// ValidateTag - tag of validation rules, see http.ValidateTag
+const ValidateTag = "validate"
+
+// FieldViolation - violation of validation rule by the field
+type FieldViolation struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// regexps - compiled expressions of regex rules
+var regexps sync.Map
+
+// Validate returns violations of ValidateTag rules by the struct pointed by out,
+// fields are named by the tag or by field name when the tag is absent.
+// Error is returned for invalid rules.
+func Validate(out interface{}, tag string) ([]FieldViolation, error) {
+	rv := reflect.ValueOf(out)
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return nil, nil
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil, nil
+	}
+
+	var violations []FieldViolation
+	err := validateStruct(rv, tag, "", &violations)
+	if err != nil {
+		return nil, err
+	}
+	return violations, nil
+}
+
+func validateStruct(rv reflect.Value, tag, prefix string, violations *[]FieldViolation) error {
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+
+		name := strings.Split(field.Tag.Get(tag), ",")[0]
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		err := validateField(rv.Field(i), field.Tag.Get(ValidateTag), tag, prefix+name, violations)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateField(value reflect.Value, rules, tag, name string, violations *[]FieldViolation) error {
+	for rules != "" {
+		var rule string
+		if strings.HasPrefix(rules, "regex=") {
+			rule, rules = rules, ""
+		} else if i := strings.IndexByte(rules, ','); i >= 0 {
+			rule, rules = rules[:i], rules[i+1:]
+		} else {
+			rule, rules = rules, ""
+		}
+
+		violation, err := checkRule(value, strings.TrimSpace(rule))
+		if err != nil {
+			return fmt.Errorf("validate: field %s: %v", name, err)
+		}
+		if violation != nil {
+			violation.Field = name
+			*violations = append(*violations, *violation)
+
+			// other rules make no sense for the missing value
+			if violation.Rule == "required" {
+				return nil
+			}
+		}
+	}
+
+	return validateNested(value, tag, name, violations)
+}
+
+// validateNested validates structs within the value.
+func validateNested(value reflect.Value, tag, name string, violations *[]FieldViolation) error {
+	switch value.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if value.IsNil() {
+			return nil
+		}
+		return validateNested(value.Elem(), tag, name, violations)
+	case reflect.Struct:
+		if value.Type() == timeType {
+			return nil
+		}
+		return validateStruct(value, tag, name+".", violations)
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			err := validateNested(value.Index(i), tag, name+"["+strconv.Itoa(i)+"]", violations)
+			if err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		iter := value.MapRange()
+		for iter.Next() {
+			err := validateNested(iter.Value(), tag, fmt.Sprintf("%s[%v]", name, iter.Key()), violations)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// checkRule returns violation of the rule by the value, error is returned for invalid rules.
+func checkRule(value reflect.Value, rule string) (*FieldViolation, error) {
+	if rule == "" {
+		return nil, nil
+	}
+
+	key, param := rule, ""
+	if i := strings.IndexByte(rule, '='); i >= 0 {
+		key, param = rule[:i], rule[i+1:]
+	}
+
+	if key == "required" {
+		if value.IsZero() || (value.Kind() == reflect.Slice || value.Kind() == reflect.Map) && value.Len() == 0 {
+			return &FieldViolation{Rule: key, Message: "is required"}, nil
+		}
+		return nil, nil
+	}
+
+	for value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			return nil, nil
+		}
+		value = value.Elem()
+	}
+
+	switch key {
+	case "min", "max":
+		limit, err := strconv.ParseFloat(param, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s rule: %s", key, param)
+		}
+
+		n, unit, ok := measure(value)
+		if !ok {
+			return nil, fmt.Errorf("%s rule is not supported for %s", key, value.Type())
+		}
+
+		if key == "min" && n < limit {
+			return &FieldViolation{Rule: key, Message: "must be at least " + param + unit}, nil
+		}
+		if key == "max" && n > limit {
+			return &FieldViolation{Rule: key, Message: "must be at most " + param + unit}, nil
+		}
+	case "oneof":
+		options := strings.Fields(param)
+		s := fmt.Sprint(value.Interface())
+		for _, option := range options {
+			if s == option {
+				return nil, nil
+			}
+		}
+		return &FieldViolation{Rule: key, Message: "must be one of " + strings.Join(options, ", ")}, nil
+	case "regex":
+		if value.Kind() != reflect.String {
+			return nil, fmt.Errorf("regex rule is not supported for %s", value.Type())
+		}
+
+		re, err := compileRegexp(param)
+		if err != nil {
+			return nil, err
+		}
+		if !re.MatchString(value.String()) {
+			return &FieldViolation{Rule: key, Message: "must match " + param}, nil
+		}
+	default:
+		return nil, fmt.Errorf("unknown rule %s", key)
+	}
+
+	return nil, nil
+}
+
+// measure returns number or length of the value and unit of the length.
+func measure(value reflect.Value) (float64, string, bool) {
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(value.Int()), "", true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(value.Uint()), "", true
+	case reflect.Float32, reflect.Float64:
+		return value.Float(), "", true
+	case reflect.String:
+		return float64(utf8.RuneCountInString(value.String())), " characters", true
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return float64(value.Len()), " items", true
+	}
+	return 0, "", false
+}
+
+func compileRegexp(expr string) (*regexp.Regexp, error) {
+	if re, ok := regexps.Load(expr); ok {
+		return re.(*regexp.Regexp), nil
+	}
+
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, err
+	}
+	regexps.Store(expr, re)
+	return re, nil
+}
@@ -0,0 +1,66 @@
+package response
+
+import (
+	nethttp "net/http"
+	"strings"
+	"time"
+)
+
+// Cookie - data for Set-Cookie response header
+type Cookie struct {
+	Name        string    `json:"name"`
+	Value       string    `json:"value"`
+	Path        string    `json:"path"`
+	Domain      string    `json:"domain"`
+	MaxAge      int       `json:"max_age"`
+	Expires     time.Time `json:"expires"`
+	Secure      bool      `json:"secure"`
+	HTTPOnly    bool      `json:"http_only"`
+	SameSite    string    `json:"same_site"`
+	SessionOnly bool      `json:"session_only"`
+}
+
+// Cookie SameSite values, lax mode is used when SameSite is empty
+const (
+	CookieSameSiteDisabled   = "disabled"
+	CookieSameSiteLaxMode    = "lax"
+	CookieSameSiteStrictMode = "strict"
+	CookieSameSiteNoneMode   = "none"
+)
+
+// CookieExpireDelete - expiration time of cleared cookies
+var CookieExpireDelete = time.Date(2009, time.November, 10, 23, 0, 0, 0, time.UTC)
+
+// StdCookie converts the cookie to net/http cookie the way Fiber sets cookies.
+func StdCookie(cookie *Cookie) *nethttp.Cookie {
+	c := &nethttp.Cookie{
+		Name:     cookie.Name,
+		Value:    cookie.Value,
+		Path:     cookie.Path,
+		Domain:   cookie.Domain,
+		Secure:   cookie.Secure,
+		HttpOnly: cookie.HTTPOnly,
+	}
+
+	if !cookie.SessionOnly {
+		c.MaxAge = cookie.MaxAge
+		c.Expires = cookie.Expires
+	}
+
+	switch strings.ToLower(cookie.SameSite) {
+	case CookieSameSiteStrictMode:
+		c.SameSite = nethttp.SameSiteStrictMode
+	case CookieSameSiteNoneMode:
+		c.SameSite = nethttp.SameSiteNoneMode
+	case CookieSameSiteDisabled:
+		c.SameSite = nethttp.SameSiteDefaultMode
+	default:
+		c.SameSite = nethttp.SameSiteLaxMode
+	}
+	return c
+}
+
+// ExpiredCookie returns net/http cookie which clears the cookie of the name.
+func ExpiredCookie(name string) *nethttp.Cookie {
+	return &nethttp.Cookie{Name: name, Expires: CookieExpireDelete}
+}
@@ -0,0 +1,33 @@
+package response
+
+import (
+	"github.com/ok93-01-18/go-ms-lib/views"
+)
+
+// EmbedParam - parameter of layout template, which is replaced by the rendered template
+const EmbedParam = "{{embed}}"
+
+// HTMLContentType - content type of rendered templates
+const HTMLContentType = "text/html; charset=utf-8"
+
+// Render renders the template and wraps it by layouts from inner to outer like http.Context.Render does.
+func Render(engine views.Engine, name string, params map[string]string, layouts []string) (string, error) {
+	body, err := engine.Render(name, params)
+	if err != nil {
+		return "", err
+	}
+
+	for _, layout := range layouts {
+		layoutParams := make(map[string]string, len(params)+1)
+		for key, value := range params {
+			layoutParams[key] = value
+		}
+		layoutParams[EmbedParam] = body
+
+		body, err = engine.Render(layout, layoutParams)
+		if err != nil {
+			return "", err
+		}
+	}
+	return body, nil
+}
@@ -0,0 +1,10 @@
+package response
+
+// SetEventStreamHeaders sets headers of the event stream response by the function, e.g. http.Context.Set.
+func SetEventStreamHeaders(set func(key, value string)) {
+	set("Content-Type", "text/event-stream")
+	set("Cache-Control", "no-cache")
+
+	// disables response buffering of nginx
+	set("X-Accel-Buffering", "no")
+}
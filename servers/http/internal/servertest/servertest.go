@@ -0,0 +1,84 @@
+// Package servertest - helpers of the behavioural test suite, which runs the same tests against every Server backend
+package servertest
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/ok93-01-18/go-ms-lib/servers/http"
+	"net"
+	"testing"
+	"time"
+)
+
+// shutdownTimeout - timeout of shutting down servers started by Serve
+const shutdownTimeout = 5 * time.Second
+
+// Backend - Server implementation under test
+type Backend struct {
+	// Name - name of the subtest
+	Name string
+
+	// New returns new server of the backend
+	New func() http.Server
+}
+
+// Backends returns all Server implementations.
+func Backends() []Backend {
+	return []Backend{
+		{
+			Name: "std",
+			New: func() http.Server {
+				return http.NewStdServer(nil)
+			},
+		},
+		{
+			Name: "fiber",
+			New: func() http.Server {
+				return http.NewFiberServer(fiber.New(fiber.Config{DisableStartupMessage: true}))
+			},
+		},
+	}
+}
+
+// Run runs the test against new server of each backend in parallel subtests.
+func Run(t *testing.T, test func(t *testing.T, server http.Server)) {
+	t.Helper()
+
+	for _, backend := range Backends() {
+		backend := backend
+		t.Run(backend.Name, func(t *testing.T) {
+			t.Parallel()
+			test(t, backend.New())
+		})
+	}
+}
+
+// Serve serves connections of new local listener by the server until the test ends and returns its address,
+// so behaviour which can't be tested by Server.Test, e.g. streaming or upgrades, is tested over the network.
+func Serve(t *testing.T, server http.Server) string {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("servertest: can't listen: %v", err)
+	}
+	ServeListener(t, server, ln)
+	return ln.Addr().String()
+}
+
+// ServeListener serves connections of the listener by the server until the test ends.
+func ServeListener(t *testing.T, server http.Server, ln net.Listener) {
+	t.Helper()
+
+	served := make(chan error, 1)
+	go func() {
+		served <- server.Listener(ln)
+	}()
+
+	t.Cleanup(func() {
+		err := server.ShutdownWithTimeout(shutdownTimeout)
+		if err != nil {
+			t.Errorf("servertest: shutdown: %v", err)
+		}
+		<-served
+	})
+}
@@ -0,0 +1,232 @@
+package cache
+
+import (
+	"github.com/ok93-01-18/go-ms-lib/controllers"
+	"github.com/ok93-01-18/go-ms-lib/servers/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultExpiration - TTL of cached responses used when Config.Expiration is zero
+const DefaultExpiration = time.Minute
+
+// HeaderXCache - response header with cache status of the response
+const HeaderXCache = "X-Cache"
+
+// Cache statuses of HeaderXCache
+const (
+	StatusHit    = "HIT"
+	StatusMiss   = "MISS"
+	StatusBypass = "BYPASS"
+)
+
+// skipHeaders - response headers which are not stored in cache
+var skipHeaders = map[string]struct{}{
+	"Content-Length":    {},
+	"Connection":        {},
+	"Date":              {},
+	"Set-Cookie":        {},
+	"Transfer-Encoding": {},
+	HeaderXCache:        {},
+}
+
+type Config struct {
+	// Cacher stores cached responses.
+	Cacher controllers.Cacher
+
+	// Expiration - TTL of cached responses, DefaultExpiration is used when zero.
+	// Response Cache-Control max-age or s-maxage directives take precedence.
+	Expiration time.Duration
+
+	// ExpirationFunc returns TTL for the request, Expiration is used when it is nil or returns zero.
+	ExpirationFunc func(http.Context) time.Duration
+
+	// VaryHeaders - request headers which values are included in the cache key.
+	VaryHeaders []string
+
+	// KeyPrefix - prefix of cache keys to separate them from other values of the Cacher.
+	KeyPrefix string
+
+	// Skip - function to bypass the cache for the request.
+	Skip func(http.Context) bool
+}
+
+// entry - cached response
+type entry struct {
+	status  int
+	headers [][2]string
+	body    []byte
+
+	// shared - response is explicitly cacheable by shared caches, so it can be served to requests with credentials
+	shared bool
+}
+
+// New - return middleware which caches full responses of GET and HEAD requests with StatusOK.
+// Responses to requests with Authorization or Cookie headers are cached and served to them
+// only if they are explicitly shared by Cache-Control public or s-maxage directives.
+func New(conf *Config) http.Handler {
+	expiration := conf.Expiration
+	if expiration == 0 {
+		expiration = DefaultExpiration
+	}
+
+	return func(ctx http.Context) error {
+		method := ctx.Method()
+		if method != "GET" && method != "HEAD" {
+			return ctx.Next()
+		}
+
+		reqDirectives := parseCacheControl(ctx.Get("Cache-Control"))
+		if _, ok := reqDirectives["no-store"]; ok || (conf.Skip != nil && conf.Skip(ctx)) {
+			ctx.Set(HeaderXCache, StatusBypass)
+			return ctx.Next()
+		}
+
+		key := cacheKey(ctx, conf.KeyPrefix, conf.VaryHeaders)
+		credentials := ctx.Get("Authorization") != "" || ctx.Get("Cookie") != ""
+
+		// no-cache request requires a fresh response, which replaces the cached one
+		if _, ok := reqDirectives["no-cache"]; !ok {
+			if value, found := conf.Cacher.Get(key); found {
+				if e, ok := value.(*entry); ok && (e.shared || !credentials) {
+					return e.write(ctx)
+				}
+			}
+		}
+
+		ctx.Set(HeaderXCache, StatusMiss)
+		err := ctx.Next()
+		if err != nil {
+			return err
+		}
+
+		response := ctx.Response()
+		if response.StatusCode() != http.StatusOK || response.Header("Set-Cookie") != "" {
+			return nil
+		}
+
+		ttl := expiration
+		if conf.ExpirationFunc != nil {
+			if d := conf.ExpirationFunc(ctx); d != 0 {
+				ttl = d
+			}
+		}
+
+		directives := parseCacheControl(response.Header("Cache-Control"))
+		shared := isShared(directives)
+		if credentials && !shared {
+			return nil
+		}
+
+		ttl, ok := responseTTL(directives, ttl)
+		if !ok {
+			return nil
+		}
+
+		e := newEntry(response)
+		e.shared = shared
+		conf.Cacher.SetWithTTL(key, e, int64(len(e.body))+1, ttl)
+
+		return nil
+	}
+}
+
+func newEntry(response http.Response) *entry {
+	e := &entry{status: response.StatusCode()}
+
+	response.VisitHeaders(func(key, value string) {
+		if _, skip := skipHeaders[key]; !skip {
+			e.headers = append(e.headers, [2]string{key, value})
+		}
+	})
+
+	// body is copied, because the buffer can be reused after the request
+	body := response.Body()
+	e.body = make([]byte, len(body))
+	copy(e.body, body)
+
+	return e
+}
+
+func (e *entry) write(ctx http.Context) error {
+	seen := make(map[string]struct{}, len(e.headers))
+	for _, header := range e.headers {
+		if _, ok := seen[header[0]]; ok {
+			ctx.Append(header[0], header[1])
+			continue
+		}
+		seen[header[0]] = struct{}{}
+		ctx.Set(header[0], header[1])
+	}
+	ctx.Set(HeaderXCache, StatusHit)
+
+	_, err := ctx.Status(e.status).Write(e.body)
+	return err
+}
+
+func cacheKey(ctx http.Context, prefix string, varyHeaders []string) string {
+	var b strings.Builder
+	b.WriteString(prefix)
+	b.WriteString(ctx.Method())
+	b.WriteByte(' ')
+	b.WriteString(ctx.Request().RequestURI())
+
+	for _, header := range varyHeaders {
+		b.WriteByte('|')
+		b.WriteString(header)
+		b.WriteByte('=')
+		b.WriteString(ctx.Get(header))
+	}
+
+	return b.String()
+}
+
+// responseTTL returns TTL of the response by its Cache-Control directives and false if the response must not be stored.
+func responseTTL(directives map[string]string, ttl time.Duration) (time.Duration, bool) {
+	for _, directive := range []string{"no-store", "no-cache", "private"} {
+		if _, ok := directives[directive]; ok {
+			return 0, false
+		}
+	}
+
+	for _, directive := range []string{"s-maxage", "max-age"} {
+		value, ok := directives[directive]
+		if !ok {
+			continue
+		}
+
+		seconds, err := strconv.Atoi(value)
+		if err != nil || seconds <= 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	return ttl, true
+}
+
+// isShared checks if the response is explicitly cacheable by shared caches, see RFC 9111 section 3.5.
+func isShared(directives map[string]string) bool {
+	_, public := directives["public"]
+	_, sMaxAge := directives["s-maxage"]
+	return public || sMaxAge
+}
+
+// parseCacheControl returns Cache-Control directives with their values.
+func parseCacheControl(header string) map[string]string {
+	directives := make(map[string]string)
+	for _, part := range strings.Split(header, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		name, value := part, ""
+		if i := strings.IndexByte(part, '='); i >= 0 {
+			name, value = part[:i], strings.Trim(part[i+1:], "\"")
+		}
+		directives[strings.ToLower(name)] = value
+	}
+	return directives
+}
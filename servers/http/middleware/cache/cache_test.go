@@ -0,0 +1,164 @@
+package cache_test
+
+import (
+	"github.com/ok93-01-18/go-ms-lib/servers/http"
+	"github.com/ok93-01-18/go-ms-lib/servers/http/httpassert"
+	"github.com/ok93-01-18/go-ms-lib/servers/http/internal/servertest"
+	"github.com/ok93-01-18/go-ms-lib/servers/http/middleware/cache"
+	nethttp "net/http"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+// mapCacher - Cacher storing all values, so tests don't depend on admission of ristretto
+type mapCacher struct {
+	mu     sync.Mutex
+	values map[interface{}]interface{}
+}
+
+func (c *mapCacher) Get(key interface{}) (interface{}, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	value, ok := c.values[key]
+	return value, ok
+}
+
+func (c *mapCacher) SetWithTTL(key, value interface{}, _ int64, _ time.Duration) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.values[key] = value
+	return true
+}
+
+func (c *mapCacher) Wait() {}
+
+func (c *mapCacher) Del(key interface{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.values, key)
+}
+
+func (c *mapCacher) GetTTL(key interface{}) (time.Duration, bool) {
+	_, ok := c.Get(key)
+	return 0, ok
+}
+
+// counting registers the route responding with the number of its calls and the Cache-Control header.
+func counting(server http.Server, path, cacheControl string) {
+	calls := 0
+	var mu sync.Mutex
+	server.Get(path, func(ctx http.Context) error {
+		mu.Lock()
+		calls++
+		n := calls
+		mu.Unlock()
+
+		if cacheControl != "" {
+			ctx.Set("Cache-Control", cacheControl)
+		}
+		_, err := ctx.WriteString(strconv.Itoa(n))
+		return err
+	})
+}
+
+func newServer(server http.Server) http.Server {
+	server.Use(cache.New(&cache.Config{Cacher: &mapCacher{values: make(map[interface{}]interface{})}}))
+	return server
+}
+
+func TestCache_Hit(t *testing.T) {
+	servertest.Run(t, func(t *testing.T, server http.Server) {
+		server = newServer(server)
+		counting(server, "/items", "")
+
+		httpassert.Do(t, server, httpassert.NewRequest(nethttp.MethodGet, "/items", nil)).
+			Header(cache.HeaderXCache, cache.StatusMiss).
+			Body("1")
+		httpassert.Do(t, server, httpassert.NewRequest(nethttp.MethodGet, "/items", nil)).
+			Header(cache.HeaderXCache, cache.StatusHit).
+			Body("1")
+		httpassert.Do(t, server, httpassert.NewRequest(nethttp.MethodGet, "/items?page=2", nil)).
+			Header(cache.HeaderXCache, cache.StatusMiss).
+			Body("2")
+	})
+}
+
+func TestCache_RequestDirectives(t *testing.T) {
+	servertest.Run(t, func(t *testing.T, server http.Server) {
+		server = newServer(server)
+		counting(server, "/items", "")
+
+		req := httpassert.NewRequest(nethttp.MethodGet, "/items", nil)
+		req.Header.Set("Cache-Control", "no-store")
+		httpassert.Do(t, server, req).
+			Header(cache.HeaderXCache, cache.StatusBypass).
+			Body("1")
+
+		httpassert.Do(t, server, httpassert.NewRequest(nethttp.MethodGet, "/items", nil)).
+			Body("2")
+
+		req = httpassert.NewRequest(nethttp.MethodGet, "/items", nil)
+		req.Header.Set("Cache-Control", "no-cache")
+		httpassert.Do(t, server, req).
+			Header(cache.HeaderXCache, cache.StatusMiss).
+			Body("3")
+
+		httpassert.Do(t, server, httpassert.NewRequest(nethttp.MethodGet, "/items", nil)).
+			Header(cache.HeaderXCache, cache.StatusHit).
+			Body("3")
+	})
+}
+
+func TestCache_ResponseDirectives(t *testing.T) {
+	servertest.Run(t, func(t *testing.T, server http.Server) {
+		server = newServer(server)
+		counting(server, "/private", "private")
+		counting(server, "/no-store", "no-store")
+
+		for _, path := range []string{"/private", "/no-store"} {
+			httpassert.Do(t, server, httpassert.NewRequest(nethttp.MethodGet, path, nil)).Body("1")
+			httpassert.Do(t, server, httpassert.NewRequest(nethttp.MethodGet, path, nil)).
+				Header(cache.HeaderXCache, cache.StatusMiss).
+				Body("2")
+		}
+	})
+}
+
+func TestCache_Credentials(t *testing.T) {
+	servertest.Run(t, func(t *testing.T, server http.Server) {
+		server = newServer(server)
+		counting(server, "/me", "")
+		counting(server, "/public", "public, max-age=60")
+
+		withAuthorization := func(path, token string) *nethttp.Request {
+			req := httpassert.NewRequest(nethttp.MethodGet, path, nil)
+			req.Header.Set("Authorization", "Bearer "+token)
+			return req
+		}
+
+		// responses to requests with credentials are not shared
+		httpassert.Do(t, server, withAuthorization("/me", "alice")).Body("1")
+		httpassert.Do(t, server, withAuthorization("/me", "bob")).
+			Header(cache.HeaderXCache, cache.StatusMiss).
+			Body("2")
+
+		// anonymous responses are not served to requests with credentials
+		httpassert.Do(t, server, httpassert.NewRequest(nethttp.MethodGet, "/me", nil)).Body("3")
+		req := httpassert.NewRequest(nethttp.MethodGet, "/me", nil)
+		req.AddCookie(&nethttp.Cookie{Name: "session", Value: "alice"})
+		httpassert.Do(t, server, req).
+			Header(cache.HeaderXCache, cache.StatusMiss).
+			Body("4")
+
+		// explicitly public responses are shared
+		httpassert.Do(t, server, withAuthorization("/public", "alice")).Body("1")
+		httpassert.Do(t, server, withAuthorization("/public", "bob")).
+			Header(cache.HeaderXCache, cache.StatusHit).
+			Body("1")
+	})
+}
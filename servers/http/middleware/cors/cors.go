@@ -0,0 +1,166 @@
+package cors
+
+import (
+	"github.com/ok93-01-18/go-ms-lib/servers/http"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// DefaultAllowMethods - methods allowed when Config.AllowMethods is empty
+var DefaultAllowMethods = []string{"GET", "POST", "HEAD", "PUT", "DELETE", "PATCH"}
+
+type Config struct {
+	// AllowOrigins - origins allowed to access the resource. "*" allows any origin,
+	// wildcard subdomains are supported, e.g. "https://*.example.com".
+	// Any origin is allowed when AllowOrigins, AllowOriginPatterns and AllowOriginFunc are empty.
+	AllowOrigins []string
+
+	// AllowOriginPatterns - regular expressions of allowed origins.
+	AllowOriginPatterns []*regexp.Regexp
+
+	// AllowOriginFunc - function to allow origins in addition to AllowOrigins and AllowOriginPatterns.
+	AllowOriginFunc func(origin string) bool
+
+	// AllowMethods - methods allowed by preflight requests, DefaultAllowMethods is used when empty.
+	AllowMethods []string
+
+	// AllowHeaders - request headers allowed by preflight requests.
+	// Headers of Access-Control-Request-Headers are allowed when empty.
+	AllowHeaders []string
+
+	// ExposeHeaders - response headers which are accessible by the client.
+	ExposeHeaders []string
+
+	// AllowCredentials allows requests with cookies and authorization headers.
+	// Allowed origin is echoed instead of "*" in that case, so allowed origins must be set explicitly
+	// and "*" is not accepted.
+	AllowCredentials bool
+
+	// MaxAge - seconds the preflight response may be cached by the client, the header is not sent when zero.
+	MaxAge int
+}
+
+type originMatcher struct {
+	any      bool
+	origins  map[string]struct{}
+	patterns []*regexp.Regexp
+	fn       func(string) bool
+}
+
+func newOriginMatcher(conf *Config) *originMatcher {
+	m := &originMatcher{
+		origins:  make(map[string]struct{}),
+		patterns: conf.AllowOriginPatterns,
+		fn:       conf.AllowOriginFunc,
+	}
+
+	if len(conf.AllowOrigins) == 0 && len(conf.AllowOriginPatterns) == 0 && conf.AllowOriginFunc == nil {
+		m.any = true
+	}
+
+	for _, origin := range conf.AllowOrigins {
+		origin = strings.ToLower(strings.TrimSpace(origin))
+		switch {
+		case origin == "*":
+			m.any = true
+		case strings.Contains(origin, "*"):
+			pattern := strings.ReplaceAll(regexp.QuoteMeta(origin), `\*`, `[a-z0-9-]+(\.[a-z0-9-]+)*`)
+			m.patterns = append(m.patterns, regexp.MustCompile("^"+pattern+"$"))
+		default:
+			m.origins[origin] = struct{}{}
+		}
+	}
+
+	return m
+}
+
+func (m *originMatcher) match(origin string) bool {
+	if m.any {
+		return true
+	}
+
+	lower := strings.ToLower(origin)
+	if _, ok := m.origins[lower]; ok {
+		return true
+	}
+
+	for _, pattern := range m.patterns {
+		if pattern.MatchString(lower) {
+			return true
+		}
+	}
+
+	return m.fn != nil && m.fn(origin)
+}
+
+// New - return middleware which handles CORS preflight requests and sets CORS headers of responses.
+// Register it by Router.Use to handle preflight requests of all routes or with the route specific Options handler.
+// It panics if credentials are allowed for any origin, which would let any site make authenticated requests on behalf of users.
+func New(conf *Config) http.Handler {
+	matcher := newOriginMatcher(conf)
+	if matcher.any && conf.AllowCredentials {
+		panic("cors: AllowCredentials requires explicit allowed origins, any origin is not accepted")
+	}
+
+	allowMethods := conf.AllowMethods
+	if len(allowMethods) == 0 {
+		allowMethods = DefaultAllowMethods
+	}
+
+	methods := strings.Join(allowMethods, ", ")
+	headers := strings.Join(conf.AllowHeaders, ", ")
+	exposeHeaders := strings.Join(conf.ExposeHeaders, ", ")
+	maxAge := strconv.Itoa(conf.MaxAge)
+
+	return func(ctx http.Context) error {
+		origin := ctx.Get("Origin")
+		preflight := ctx.Method() == "OPTIONS" && ctx.Get("Access-Control-Request-Method") != ""
+
+		// response depends on the origin unless any origin gets "*"
+		if !matcher.any {
+			ctx.Append("Vary", "Origin")
+		}
+
+		if origin == "" || !matcher.match(origin) {
+			if preflight {
+				return ctx.SendStatus(http.StatusNoContent)
+			}
+			return ctx.Next()
+		}
+
+		allowOrigin := origin
+		if matcher.any {
+			allowOrigin = "*"
+		}
+		ctx.Set("Access-Control-Allow-Origin", allowOrigin)
+
+		if conf.AllowCredentials {
+			ctx.Set("Access-Control-Allow-Credentials", "true")
+		}
+
+		if !preflight {
+			if exposeHeaders != "" {
+				ctx.Set("Access-Control-Expose-Headers", exposeHeaders)
+			}
+			return ctx.Next()
+		}
+
+		ctx.Append("Vary", "Access-Control-Request-Method", "Access-Control-Request-Headers")
+		ctx.Set("Access-Control-Allow-Methods", methods)
+
+		allowHeaders := headers
+		if allowHeaders == "" {
+			allowHeaders = ctx.Get("Access-Control-Request-Headers")
+		}
+		if allowHeaders != "" {
+			ctx.Set("Access-Control-Allow-Headers", allowHeaders)
+		}
+
+		if conf.MaxAge > 0 {
+			ctx.Set("Access-Control-Max-Age", maxAge)
+		}
+
+		return ctx.SendStatus(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,101 @@
+package cors_test
+
+import (
+	"github.com/ok93-01-18/go-ms-lib/servers/http"
+	"github.com/ok93-01-18/go-ms-lib/servers/http/httpassert"
+	"github.com/ok93-01-18/go-ms-lib/servers/http/internal/servertest"
+	"github.com/ok93-01-18/go-ms-lib/servers/http/middleware/cors"
+	nethttp "net/http"
+	"testing"
+)
+
+func newServer(t *testing.T, server http.Server, conf *cors.Config) http.Server {
+	t.Helper()
+
+	server.Use(cors.New(conf))
+	server.Get("/", func(ctx http.Context) error {
+		return ctx.SendStatus(http.StatusOK)
+	})
+	return server
+}
+
+func request(method, origin string) *nethttp.Request {
+	req := httpassert.NewRequest(method, "/", nil)
+	req.Header.Set("Origin", origin)
+	return req
+}
+
+func preflight(origin string) *nethttp.Request {
+	req := request(nethttp.MethodOptions, origin)
+	req.Header.Set("Access-Control-Request-Method", nethttp.MethodPost)
+	req.Header.Set("Access-Control-Request-Headers", "X-Custom")
+	return req
+}
+
+func TestCORS_AnyOrigin(t *testing.T) {
+	servertest.Run(t, func(t *testing.T, server http.Server) {
+		server = newServer(t, server, &cors.Config{})
+
+		httpassert.Do(t, server, request(nethttp.MethodGet, "https://example.com")).
+			Status(http.StatusOK).
+			Header("Access-Control-Allow-Origin", "*").
+			Header("Access-Control-Allow-Credentials", "").
+			Header("Vary", "")
+	})
+}
+
+func TestCORS_Origins(t *testing.T) {
+	servertest.Run(t, func(t *testing.T, server http.Server) {
+		server = newServer(t, server, &cors.Config{
+			AllowOrigins:     []string{"https://example.com", "https://*.example.org"},
+			AllowCredentials: true,
+			ExposeHeaders:    []string{"X-Total"},
+		})
+
+		httpassert.Do(t, server, request(nethttp.MethodGet, "https://example.com")).
+			Status(http.StatusOK).
+			Header("Access-Control-Allow-Origin", "https://example.com").
+			Header("Access-Control-Allow-Credentials", "true").
+			Header("Access-Control-Expose-Headers", "X-Total").
+			Header("Vary", "Origin")
+		httpassert.Do(t, server, request(nethttp.MethodGet, "https://api.example.org")).
+			Header("Access-Control-Allow-Origin", "https://api.example.org")
+		httpassert.Do(t, server, request(nethttp.MethodGet, "https://evil.com")).
+			Status(http.StatusOK).
+			Header("Access-Control-Allow-Origin", "").
+			Header("Access-Control-Allow-Credentials", "")
+	})
+}
+
+func TestCORS_Preflight(t *testing.T) {
+	servertest.Run(t, func(t *testing.T, server http.Server) {
+		server = newServer(t, server, &cors.Config{AllowOrigins: []string{"https://example.com"}, MaxAge: 600})
+
+		httpassert.Do(t, server, preflight("https://example.com")).
+			Status(http.StatusNoContent).
+			Header("Access-Control-Allow-Origin", "https://example.com").
+			Header("Access-Control-Allow-Methods", "GET, POST, HEAD, PUT, DELETE, PATCH").
+			Header("Access-Control-Allow-Headers", "X-Custom").
+			Header("Access-Control-Max-Age", "600")
+		httpassert.Do(t, server, preflight("https://evil.com")).
+			Status(http.StatusNoContent).
+			Header("Access-Control-Allow-Origin", "")
+	})
+}
+
+func TestNew_CredentialsAnyOrigin(t *testing.T) {
+	for _, conf := range []*cors.Config{
+		{AllowCredentials: true},
+		{AllowCredentials: true, AllowOrigins: []string{"*"}},
+		{AllowCredentials: true, AllowOrigins: []string{"https://example.com", "*"}},
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("New(%+v) didn't panic", conf)
+				}
+			}()
+			cors.New(conf)
+		}()
+	}
+}
@@ -0,0 +1,117 @@
+package limiter
+
+import (
+	"math"
+	"time"
+)
+
+// Result - decision of Algorithm about the request
+type Result struct {
+	// Allowed is true if the request is within the limit.
+	Allowed bool
+
+	// Remaining - number of requests which are allowed right now.
+	Remaining int
+
+	// Reset - duration until the limit is fully restored.
+	Reset time.Duration
+
+	// RetryAfter - duration until the next request is allowed, set when the request is not allowed.
+	RetryAfter time.Duration
+
+	// TTL - duration to keep the state in the store.
+	TTL time.Duration
+}
+
+// Algorithm - rate limiting algorithm
+type Algorithm interface {
+	// Take accounts the request at the moment now for the state of the key and returns the decision with the new state.
+	// The state is nil for the first request of the key.
+	Take(state interface{}, now time.Time, max int, period time.Duration) (Result, interface{})
+}
+
+// TokenBucket - bucket with capacity of max tokens refilled at rate max per period, so bursts up to max are allowed
+type TokenBucket struct{}
+
+type tokenBucketState struct {
+	Tokens float64
+	Last   time.Time
+}
+
+func (TokenBucket) Take(state interface{}, now time.Time, max int, period time.Duration) (Result, interface{}) {
+	rate := float64(max) / period.Seconds()
+
+	s, ok := state.(tokenBucketState)
+	if !ok {
+		s = tokenBucketState{Tokens: float64(max), Last: now}
+	}
+
+	s.Tokens = math.Min(float64(max), s.Tokens+now.Sub(s.Last).Seconds()*rate)
+	s.Last = now
+
+	result := Result{TTL: period}
+	if s.Tokens >= 1 {
+		s.Tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = secondsDuration((1 - s.Tokens) / rate)
+	}
+
+	result.Remaining = int(s.Tokens)
+	result.Reset = secondsDuration((float64(max) - s.Tokens) / rate)
+
+	return result, s
+}
+
+// SlidingWindow - counter of requests in the sliding period, estimated by counters of the current and the previous fixed windows
+type SlidingWindow struct{}
+
+type slidingWindowState struct {
+	Start    time.Time
+	Previous int
+	Current  int
+}
+
+func (SlidingWindow) Take(state interface{}, now time.Time, max int, period time.Duration) (Result, interface{}) {
+	s, ok := state.(slidingWindowState)
+	if !ok {
+		s = slidingWindowState{Start: now.Truncate(period)}
+	}
+
+	switch elapsed := now.Sub(s.Start); {
+	case elapsed >= 2*period:
+		s = slidingWindowState{Start: now.Truncate(period)}
+	case elapsed >= period:
+		s = slidingWindowState{Start: s.Start.Add(period), Previous: s.Current}
+	}
+
+	elapsed := now.Sub(s.Start)
+	weight := 1 - float64(elapsed)/float64(period)
+	estimate := float64(s.Previous)*weight + float64(s.Current)
+
+	result := Result{
+		Reset: period - elapsed,
+		TTL:   2*period - elapsed,
+	}
+
+	if estimate+1 <= float64(max) {
+		s.Current++
+		estimate++
+		result.Allowed = true
+	} else {
+		result.RetryAfter = period - elapsed
+		// the request is allowed when the weight of the previous window decreases enough
+		if s.Previous > 0 && s.Current+1 <= max {
+			allowedWeight := float64(max-s.Current-1) / float64(s.Previous)
+			result.RetryAfter = time.Duration((1-allowedWeight)*float64(period)) - elapsed
+		}
+	}
+
+	result.Remaining = int(math.Max(0, math.Floor(float64(max)-estimate)))
+
+	return result, s
+}
+
+func secondsDuration(seconds float64) time.Duration {
+	return time.Duration(seconds * float64(time.Second))
+}
@@ -0,0 +1,147 @@
+package limiter
+
+import (
+	"github.com/ok93-01-18/go-ms-lib/servers/http"
+	"hash/fnv"
+	"math"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Defaults used for zero values of Config
+const (
+	DefaultMax    = 10
+	DefaultPeriod = time.Minute
+)
+
+// lockStripes - number of locks serializing requests of keys, requests of different keys rarely wait for each other
+const lockStripes = 256
+
+// Response headers with limiter state
+const (
+	HeaderLimit      = "X-RateLimit-Limit"
+	HeaderRemaining  = "X-RateLimit-Remaining"
+	HeaderReset      = "X-RateLimit-Reset"
+	HeaderRetryAfter = "Retry-After"
+)
+
+type Config struct {
+	// Max - number of requests allowed per Period for the key, DefaultMax is used when zero.
+	Max int
+
+	// Period - time window of Max requests, DefaultPeriod is used when zero.
+	Period time.Duration
+
+	// Algorithm of rate limiting, SlidingWindow is used when nil.
+	Algorithm Algorithm
+
+	// Store keeps limiter states of keys, new MemoryStore is used when nil.
+	// Requests of the key are serialized by the middleware, so the store doesn't need atomic updates.
+	Store Store
+
+	// KeyGenerator returns the key of the client, Context.IP is used when nil.
+	KeyGenerator func(http.Context) string
+
+	// KeyPrefix - prefix of store keys to separate limiters which share the store.
+	KeyPrefix string
+
+	// Skip - function to skip limiting of the request.
+	Skip func(http.Context) bool
+
+	// LimitReached handles requests over the limit, TooManyRequests error is returned when nil.
+	LimitReached http.Handler
+}
+
+// New - return middleware which limits number of requests per client key
+func New(conf *Config) http.Handler {
+	max := conf.Max
+	if max == 0 {
+		max = DefaultMax
+	}
+
+	period := conf.Period
+	if period == 0 {
+		period = DefaultPeriod
+	}
+
+	algorithm := conf.Algorithm
+	if algorithm == nil {
+		algorithm = SlidingWindow{}
+	}
+
+	store := conf.Store
+	if store == nil {
+		store = NewMemoryStore()
+	}
+
+	keyGenerator := conf.KeyGenerator
+	if keyGenerator == nil {
+		keyGenerator = func(ctx http.Context) string {
+			return ctx.IP()
+		}
+	}
+
+	limitReached := conf.LimitReached
+	if limitReached == nil {
+		limitReached = func(ctx http.Context) error {
+			return http.TooManyRequests()
+		}
+	}
+
+	// state is read and written back by the same request, so the key must be locked in between
+	var locks keyLocks
+
+	return func(ctx http.Context) error {
+		if conf.Skip != nil && conf.Skip(ctx) {
+			return ctx.Next()
+		}
+
+		key := conf.KeyPrefix + keyGenerator(ctx)
+		now := time.Now()
+
+		mu := locks.get(key)
+		mu.Lock()
+		state, _ := store.Get(key)
+		result, state := algorithm.Take(state, now, max, period)
+		store.Set(key, state, result.TTL)
+		mu.Unlock()
+
+		ctx.Set(HeaderLimit, strconv.Itoa(max))
+		ctx.Set(HeaderRemaining, strconv.Itoa(result.Remaining))
+		ctx.Set(HeaderReset, strconv.Itoa(seconds(result.Reset)))
+
+		if !result.Allowed {
+			ctx.Set(HeaderRetryAfter, strconv.Itoa(seconds(result.RetryAfter)))
+			return limitReached(ctx)
+		}
+
+		return ctx.Next()
+	}
+}
+
+// KeyByHeader - return key generator which uses the request header, e.g. API key,
+// and falls back to the client IP when the header is empty
+func KeyByHeader(header string) func(http.Context) string {
+	return func(ctx http.Context) string {
+		if value := ctx.Get(header); value != "" {
+			return header + ":" + value
+		}
+		return ctx.IP()
+	}
+}
+
+// keyLocks - striped locks of keys
+type keyLocks [lockStripes]sync.Mutex
+
+// get returns the lock of the key.
+func (l *keyLocks) get(key string) *sync.Mutex {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(key))
+	return &l[h.Sum32()%lockStripes]
+}
+
+// seconds rounds the duration up to whole seconds.
+func seconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
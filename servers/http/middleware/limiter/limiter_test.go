@@ -0,0 +1,109 @@
+package limiter_test
+
+import (
+	"github.com/ok93-01-18/go-ms-lib/servers/http"
+	"github.com/ok93-01-18/go-ms-lib/servers/http/httpassert"
+	"github.com/ok93-01-18/go-ms-lib/servers/http/internal/servertest"
+	"github.com/ok93-01-18/go-ms-lib/servers/http/middleware/limiter"
+	nethttp "net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func ok(ctx http.Context) error {
+	return ctx.SendStatus(http.StatusOK)
+}
+
+func TestLimiter_Limit(t *testing.T) {
+	for _, algorithm := range []limiter.Algorithm{limiter.SlidingWindow{}, limiter.TokenBucket{}} {
+		algorithm := algorithm
+		servertest.Run(t, func(t *testing.T, server http.Server) {
+			server.Use(limiter.New(&limiter.Config{Max: 2, Period: time.Hour, Algorithm: algorithm}))
+			server.Get("/", ok)
+
+			httpassert.Do(t, server, httpassert.NewRequest(nethttp.MethodGet, "/", nil)).
+				Status(http.StatusOK).
+				Header(limiter.HeaderLimit, "2").
+				Header(limiter.HeaderRemaining, "1")
+			httpassert.Do(t, server, httpassert.NewRequest(nethttp.MethodGet, "/", nil)).
+				Status(http.StatusOK).
+				Header(limiter.HeaderRemaining, "0")
+
+			resp := httpassert.Do(t, server, httpassert.NewRequest(nethttp.MethodGet, "/", nil)).
+				Status(http.StatusTooManyRequests).
+				Header(limiter.HeaderRemaining, "0")
+			if resp.Response.Header.Get(limiter.HeaderRetryAfter) == "" {
+				t.Errorf("%s header is not set", limiter.HeaderRetryAfter)
+			}
+		})
+	}
+}
+
+func TestLimiter_KeyByHeader(t *testing.T) {
+	servertest.Run(t, func(t *testing.T, server http.Server) {
+		server.Use(limiter.New(&limiter.Config{Max: 1, Period: time.Hour, KeyGenerator: limiter.KeyByHeader("X-API-Key")}))
+		server.Get("/", ok)
+
+		withKey := func(key string) *nethttp.Request {
+			req := httpassert.NewRequest(nethttp.MethodGet, "/", nil)
+			req.Header.Set("X-API-Key", key)
+			return req
+		}
+
+		httpassert.Do(t, server, withKey("a")).Status(http.StatusOK)
+		httpassert.Do(t, server, withKey("b")).Status(http.StatusOK)
+		httpassert.Do(t, server, withKey("a")).Status(http.StatusTooManyRequests)
+	})
+}
+
+func TestLimiter_Skip(t *testing.T) {
+	servertest.Run(t, func(t *testing.T, server http.Server) {
+		server.Use(limiter.New(&limiter.Config{
+			Max:    1,
+			Period: time.Hour,
+			Skip: func(ctx http.Context) bool {
+				return ctx.Request().Path() == "/health"
+			},
+		}))
+		server.Get("/health", ok)
+
+		for i := 0; i < 3; i++ {
+			httpassert.Do(t, server, httpassert.NewRequest(nethttp.MethodGet, "/health", nil)).
+				Status(http.StatusOK)
+		}
+	})
+}
+
+func TestLimiter_Concurrent(t *testing.T) {
+	const max = 20
+
+	servertest.Run(t, func(t *testing.T, server http.Server) {
+		server.Use(limiter.New(&limiter.Config{Max: max, Period: time.Hour}))
+		server.Get("/", ok)
+
+		var allowed int32
+		var wg sync.WaitGroup
+		for i := 0; i < 3*max; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				resp, err := server.Test(httpassert.NewRequest(nethttp.MethodGet, "/", nil), httpassert.DefaultTimeout)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				_ = resp.Body.Close()
+				if resp.StatusCode == http.StatusOK {
+					atomic.AddInt32(&allowed, 1)
+				}
+			}()
+		}
+		wg.Wait()
+
+		if allowed != max {
+			t.Errorf("allowed %d requests, want %d", allowed, max)
+		}
+	})
+}
@@ -0,0 +1,102 @@
+package limiter
+
+import (
+	"github.com/ok93-01-18/go-ms-lib/controllers"
+	"sync"
+	"time"
+)
+
+// Store - storage of limiter states.
+// States must be kept until their TTL expires, stores which may reject or evict them,
+// like caches with admission policies, let clients exceed limits.
+type Store interface {
+	// Get returns the state by key and a boolean representing whether the state was found or not.
+	Get(string) (interface{}, bool)
+
+	// Set stores the state by key for the TTL.
+	Set(string, interface{}, time.Duration)
+}
+
+// memoryCleanupInterval - interval of removal of expired states from MemoryStore
+const memoryCleanupInterval = time.Minute
+
+type memoryItem struct {
+	value     interface{}
+	expiresAt time.Time
+}
+
+// MemoryStore - in-memory store of limiter states
+type MemoryStore struct {
+	sync.Mutex
+	items       map[string]memoryItem
+	lastCleanup time.Time
+}
+
+func (s *MemoryStore) Get(key string) (interface{}, bool) {
+	s.Lock()
+	defer s.Unlock()
+
+	item, ok := s.items[key]
+	if !ok || time.Now().After(item.expiresAt) {
+		return nil, false
+	}
+	return item.value, true
+}
+
+func (s *MemoryStore) Set(key string, value interface{}, ttl time.Duration) {
+	now := time.Now()
+
+	s.Lock()
+	defer s.Unlock()
+
+	s.items[key] = memoryItem{value: value, expiresAt: now.Add(ttl)}
+
+	if now.Sub(s.lastCleanup) < memoryCleanupInterval {
+		return
+	}
+	s.lastCleanup = now
+	for k, item := range s.items {
+		if now.After(item.expiresAt) {
+			delete(s.items, k)
+		}
+	}
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		items:       make(map[string]memoryItem),
+		lastCleanup: time.Now(),
+	}
+}
+
+// CacherStore - store of limiter states built on controllers.Cacher.
+// Guarantees are weaker than of MemoryStore: caches with admission policies, like controllers.Cache,
+// may reject new states or evict kept ones when the cache is full, so clients exceed limits until
+// their states are admitted. Size the cache for all active keys to keep limits exact.
+type CacherStore struct {
+	cacher controllers.Cacher
+	locks  keyLocks
+}
+
+func (s *CacherStore) Get(key string) (interface{}, bool) {
+	mu := s.locks.get(key)
+	mu.Lock()
+	defer mu.Unlock()
+
+	return s.cacher.Get(key)
+}
+
+// Set waits until the state is applied while the key is locked, so the next Get of the key sees it.
+func (s *CacherStore) Set(key string, value interface{}, ttl time.Duration) {
+	mu := s.locks.get(key)
+	mu.Lock()
+	defer mu.Unlock()
+
+	if s.cacher.SetWithTTL(key, value, 1, ttl) {
+		s.cacher.Wait()
+	}
+}
+
+func NewCacherStore(cacher controllers.Cacher) *CacherStore {
+	return &CacherStore{cacher: cacher}
+}
@@ -0,0 +1,60 @@
+package limiter_test
+
+import (
+	"github.com/ok93-01-18/go-ms-lib/controllers"
+	"github.com/ok93-01-18/go-ms-lib/servers/http"
+	"github.com/ok93-01-18/go-ms-lib/servers/http/httpassert"
+	"github.com/ok93-01-18/go-ms-lib/servers/http/internal/servertest"
+	"github.com/ok93-01-18/go-ms-lib/servers/http/middleware/limiter"
+	nethttp "net/http"
+	"testing"
+	"time"
+)
+
+func newCacherStore(t *testing.T) *limiter.CacherStore {
+	t.Helper()
+
+	cacher, err := controllers.NewControllerCache(&controllers.Config{NumCounters: 1000, MaxCost: 100, BufferItems: 64})
+	if err != nil {
+		t.Fatalf("NewControllerCache() = %v", err)
+	}
+	return limiter.NewCacherStore(cacher)
+}
+
+func testStore(t *testing.T, store limiter.Store) {
+	t.Helper()
+
+	if _, ok := store.Get("a"); ok {
+		t.Error("Get() of missing key found the state")
+	}
+
+	store.Set("a", 1, time.Hour)
+	if value, ok := store.Get("a"); !ok || value != 1 {
+		t.Errorf("Get() = %v, %t, want 1, true", value, ok)
+	}
+
+	store.Set("b", 2, 10*time.Millisecond)
+	time.Sleep(20 * time.Millisecond)
+	if _, ok := store.Get("b"); ok {
+		t.Error("Get() of expired key found the state")
+	}
+}
+
+func TestMemoryStore(t *testing.T) {
+	testStore(t, limiter.NewMemoryStore())
+}
+
+func TestCacherStore(t *testing.T) {
+	testStore(t, newCacherStore(t))
+}
+
+func TestLimiter_CacherStore(t *testing.T) {
+	servertest.Run(t, func(t *testing.T, server http.Server) {
+		server.Use(limiter.New(&limiter.Config{Max: 2, Period: time.Hour, Store: newCacherStore(t)}))
+		server.Get("/", ok)
+
+		httpassert.Do(t, server, httpassert.NewRequest(nethttp.MethodGet, "/", nil)).Status(http.StatusOK)
+		httpassert.Do(t, server, httpassert.NewRequest(nethttp.MethodGet, "/", nil)).Status(http.StatusOK)
+		httpassert.Do(t, server, httpassert.NewRequest(nethttp.MethodGet, "/", nil)).Status(http.StatusTooManyRequests)
+	})
+}
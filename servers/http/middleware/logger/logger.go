@@ -0,0 +1,228 @@
+package logger
+
+import (
+	"github.com/ok93-01-18/go-ms-lib/log"
+	"github.com/ok93-01-18/go-ms-lib/servers/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Predefined record formats
+const (
+	// FormatDefault - short record with request, status, latency and size of the response
+	FormatDefault = "${ip} ${method} ${uri} ${status} ${latency} ${bytes} \"${ua}\""
+
+	// FormatCombined - Apache combined log format
+	FormatCombined = "${ip} - - [${time}] \"${method} ${uri} ${protocol}\" ${status} ${bytes} \"${referer}\" \"${ua}\""
+
+	// TimeFormatCombined - time format of Apache combined log format
+	TimeFormatCombined = "02/Jan/2006:15:04:05 -0700"
+)
+
+// Tags which can be used in Format as ${tag}
+const (
+	TagTime     = "time"
+	TagIP       = "ip"
+	TagHost     = "host"
+	TagMethod   = "method"
+	TagURI      = "uri"
+	TagPath     = "path"
+	TagProtocol = "protocol"
+	TagStatus   = "status"
+	TagLatency  = "latency"
+	TagBytes    = "bytes"
+	TagReferer  = "referer"
+	TagUA       = "ua"
+	TagError    = "error"
+
+	// TagHeader - request header value, e.g. ${header:X-Forwarded-For}
+	TagHeader = "header:"
+
+	// TagLocals - string value of Context.Locals, e.g. ${locals:user}
+	TagLocals = "locals:"
+)
+
+type Config struct {
+	// Logger receives records to the channel matching request method, see log.GetLogTypeByRequestType.
+	// It is required, New panics when it is nil.
+	Logger log.Logger
+
+	// Format of the record with ${tag} placeholders, FormatDefault is used when empty.
+	Format string
+
+	// TimeFormat of ${time} tag. TimeFormatCombined is used for FormatCombined and time.RFC3339 for others when empty.
+	TimeFormat string
+
+	// SkipPaths - request paths which are not logged, e.g. health endpoints.
+	SkipPaths []string
+
+	// Skip - function to skip logging of the request, called after the request is handled.
+	Skip func(http.Context) bool
+}
+
+type token struct {
+	literal string
+	tag     string
+}
+
+type record struct {
+	ctx     http.Context
+	start   time.Time
+	latency time.Duration
+	status  int
+	err     error
+}
+
+// New - return middleware which writes access log records of requests
+func New(conf *Config) http.Handler {
+	if conf.Logger == nil {
+		panic("logger: Config.Logger is required")
+	}
+
+	format := conf.Format
+	if format == "" {
+		format = FormatDefault
+	}
+
+	timeFormat := conf.TimeFormat
+	if timeFormat == "" {
+		timeFormat = time.RFC3339
+		if format == FormatCombined {
+			timeFormat = TimeFormatCombined
+		}
+	}
+
+	tokens := parseFormat(format)
+
+	skipPaths := make(map[string]struct{}, len(conf.SkipPaths))
+	for _, path := range conf.SkipPaths {
+		skipPaths[path] = struct{}{}
+	}
+
+	return func(ctx http.Context) error {
+		if _, ok := skipPaths[ctx.Request().Path()]; ok {
+			return ctx.Next()
+		}
+
+		start := time.Now()
+		chainErr := ctx.Next()
+
+		if conf.Skip != nil && conf.Skip(ctx) {
+			return chainErr
+		}
+
+		r := &record{
+			ctx:     ctx,
+			start:   start,
+			latency: time.Since(start),
+			status:  ctx.Response().StatusCode(),
+			err:     chainErr,
+		}
+		if chainErr != nil {
+			r.status = http.ErrorStatusCode(chainErr)
+		}
+
+		line := r.format(tokens, timeFormat)
+		logType := log.GetLogTypeByRequestType(ctx.Method())
+		switch {
+		case r.status >= http.StatusInternalServerError:
+			conf.Logger.Errorf(logType, "%s", line)
+		case r.status >= http.StatusBadRequest:
+			conf.Logger.Warnf(logType, "%s", line)
+		default:
+			conf.Logger.Infof(logType, "%s", line)
+		}
+
+		return chainErr
+	}
+}
+
+// parseFormat splits the format to literals and tags.
+func parseFormat(format string) []token {
+	var tokens []token
+	for {
+		start := strings.Index(format, "${")
+		if start < 0 {
+			break
+		}
+		end := strings.Index(format[start:], "}")
+		if end < 0 {
+			break
+		}
+
+		if start > 0 {
+			tokens = append(tokens, token{literal: format[:start]})
+		}
+		tokens = append(tokens, token{tag: format[start+2 : start+end]})
+		format = format[start+end+1:]
+	}
+
+	if format != "" {
+		tokens = append(tokens, token{literal: format})
+	}
+	return tokens
+}
+
+func (r *record) format(tokens []token, timeFormat string) string {
+	var b strings.Builder
+	for _, t := range tokens {
+		if t.tag == "" {
+			b.WriteString(t.literal)
+			continue
+		}
+		b.WriteString(r.tag(t.tag, timeFormat))
+	}
+	return b.String()
+}
+
+func (r *record) tag(tag string, timeFormat string) string {
+	ctx := r.ctx
+	switch tag {
+	case TagTime:
+		return r.start.Format(timeFormat)
+	case TagIP:
+		return ctx.IP()
+	case TagHost:
+		return ctx.Hostname()
+	case TagMethod:
+		return ctx.Method()
+	case TagURI:
+		return ctx.Request().RequestURI()
+	case TagPath:
+		return ctx.Request().Path()
+	case TagProtocol:
+		return ctx.Request().Protocol()
+	case TagStatus:
+		return strconv.Itoa(r.status)
+	case TagLatency:
+		return r.latency.Round(time.Microsecond).String()
+	case TagBytes:
+		size := ctx.Response().BodySize()
+		if size < 0 {
+			return "-"
+		}
+		return strconv.Itoa(size)
+	case TagReferer:
+		return ctx.Get("Referer")
+	case TagUA:
+		return ctx.Get("User-Agent")
+	case TagError:
+		if r.err == nil {
+			return ""
+		}
+		return r.err.Error()
+	}
+
+	switch {
+	case strings.HasPrefix(tag, TagHeader):
+		return ctx.Get(tag[len(TagHeader):])
+	case strings.HasPrefix(tag, TagLocals):
+		if value, ok := ctx.Locals(tag[len(TagLocals):]).(string); ok {
+			return value
+		}
+		return ""
+	}
+
+	return "${" + tag + "}"
+}
@@ -0,0 +1,92 @@
+package recover
+
+import (
+	"fmt"
+	"github.com/ok93-01-18/go-ms-lib/log"
+	"github.com/ok93-01-18/go-ms-lib/notification"
+	"github.com/ok93-01-18/go-ms-lib/servers/http"
+	"runtime/debug"
+	"sync"
+	"time"
+)
+
+// DefaultNotifyInterval - minimal interval between alerts used when Config.NotifyInterval is zero
+const DefaultNotifyInterval = time.Minute
+
+type Config struct {
+	// Logger receives the panic value with stack trace to the app channel.
+	Logger log.Logger
+
+	// Notifier receives summarized alerts about panics, alerts are not sent when nil.
+	Notifier notification.Notifier
+
+	// NotifyInterval - minimal interval between alerts. Panics within the interval are not published,
+	// their number is reported by the next alert. DefaultNotifyInterval is used when zero.
+	NotifyInterval time.Duration
+}
+
+// alerter - rate limiter of panic alerts
+type alerter struct {
+	sync.Mutex
+	notifier   notification.Notifier
+	interval   time.Duration
+	lastAlert  time.Time
+	suppressed int
+}
+
+func (a *alerter) alert(msg string) {
+	if a.notifier == nil {
+		return
+	}
+
+	a.Lock()
+	now := time.Now()
+	if !a.lastAlert.IsZero() && now.Sub(a.lastAlert) < a.interval {
+		a.suppressed++
+		a.Unlock()
+		return
+	}
+
+	suppressed := a.suppressed
+	a.suppressed = 0
+	a.lastAlert = now
+	a.Unlock()
+
+	if suppressed > 0 {
+		msg = fmt.Sprintf("%s (%d more panics since the previous alert)", msg, suppressed)
+	}
+	a.notifier.Publish(msg)
+}
+
+// New - return middleware which recovers panics of next handlers. The partial response of the handler is discarded
+// and InternalServerError wrapping the panic value is returned, so it is rendered by the error handler of the server.
+func New(conf *Config) http.Handler {
+	interval := conf.NotifyInterval
+	if interval == 0 {
+		interval = DefaultNotifyInterval
+	}
+
+	a := &alerter{
+		notifier: conf.Notifier,
+		interval: interval,
+	}
+
+	return func(ctx http.Context) (err error) {
+		defer func() {
+			r := recover()
+			if r == nil {
+				return
+			}
+
+			if conf.Logger != nil {
+				conf.Logger.Errorf(log.TypeApp, "panic on %s %s: %v\n%s", ctx.Method(), ctx.Request().RequestURI(), r, debug.Stack())
+			}
+			a.alert(fmt.Sprintf("panic on %s %s: %v", ctx.Method(), ctx.Request().Path(), r))
+
+			ctx.Response().Reset()
+			err = http.InternalServerError().Wrap(fmt.Errorf("panic: %v", r))
+		}()
+
+		return ctx.Next()
+	}
+}
@@ -0,0 +1,30 @@
+package recover_test
+
+import (
+	"github.com/ok93-01-18/go-ms-lib/servers/http"
+	"github.com/ok93-01-18/go-ms-lib/servers/http/httpassert"
+	"github.com/ok93-01-18/go-ms-lib/servers/http/internal/servertest"
+	"github.com/ok93-01-18/go-ms-lib/servers/http/middleware/recover"
+	nethttp "net/http"
+	"testing"
+)
+
+func TestRecover(t *testing.T) {
+	servertest.Run(t, func(t *testing.T, server http.Server) {
+		server.SetErrorHandler(func(ctx http.Context, err error) error {
+			return ctx.Status(http.ErrorStatusCode(err)).JSON(map[string]string{"error": http.AsHTTPError(err).Message})
+		})
+		server.Use(recover.New(&recover.Config{}))
+		server.Get("/", func(ctx http.Context) error {
+			ctx.Set("X-Partial", "1")
+			_, _ = ctx.WriteString("partial")
+			panic("boom")
+		})
+
+		// the partial response is discarded and the panic is rendered by the error handler
+		httpassert.Do(t, server, httpassert.NewRequest(nethttp.MethodGet, "/", nil)).
+			Status(http.StatusInternalServerError).
+			Header("X-Partial", "").
+			JSON(`{"error": "Internal Server Error"}`)
+	})
+}
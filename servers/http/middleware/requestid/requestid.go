@@ -0,0 +1,96 @@
+package requestid
+
+import (
+	"crypto/rand"
+	"fmt"
+	"github.com/ok93-01-18/go-ms-lib/log"
+	"github.com/ok93-01-18/go-ms-lib/servers/http"
+)
+
+// DefaultHeader - header of request ID used when Config.Header is empty
+const DefaultHeader = "X-Request-ID"
+
+// LocalsKey - key of request ID in Context.Locals, e.g. ${locals:requestid} tag of access log
+const LocalsKey = "requestid"
+
+// FieldRequestID - field of log records with request ID
+const FieldRequestID = "request_id"
+
+// maxLength - max length of request ID accepted from the client
+const maxLength = 128
+
+type Config struct {
+	// Header - request and response header of request ID, DefaultHeader is used when empty.
+	Header string
+
+	// Generator returns new request ID when the request has no valid one, UUID v4 is generated when nil.
+	Generator func() string
+}
+
+// New - return middleware which accepts or generates request ID, stores it in Context.Locals and echoes it in the response
+func New(conf *Config) http.Handler {
+	header := conf.Header
+	if header == "" {
+		header = DefaultHeader
+	}
+
+	generator := conf.Generator
+	if generator == nil {
+		generator = UUID
+	}
+
+	return func(ctx http.Context) error {
+		id := ctx.Get(header)
+		if !isValid(id) {
+			id = generator()
+		}
+
+		ctx.Locals(LocalsKey, id)
+		ctx.Set(header, id)
+
+		return ctx.Next()
+	}
+}
+
+// FromContext returns request ID of the request, empty string is returned if the middleware was not applied.
+func FromContext(ctx http.Context) string {
+	id, _ := ctx.Locals(LocalsKey).(string)
+	return id
+}
+
+// Logger returns the logger which adds request ID field to each record logged within the request.
+func Logger(ctx http.Context, logger log.Logger) log.Logger {
+	id := FromContext(ctx)
+	if id == "" {
+		return logger
+	}
+	return log.WithField(logger, FieldRequestID, id)
+}
+
+// UUID returns random UUID v4.
+func UUID() string {
+	var b [16]byte
+	_, err := rand.Read(b[:])
+	if err != nil {
+		panic(fmt.Sprintf("requestid: can't read random bytes: %v", err))
+	}
+
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+// isValid checks that the request ID from the client is safe to be logged and echoed.
+func isValid(id string) bool {
+	if id == "" || len(id) > maxLength {
+		return false
+	}
+
+	for i := 0; i < len(id); i++ {
+		if id[i] < 0x21 || id[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
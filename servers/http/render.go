@@ -0,0 +1,62 @@
+package http
+
+import (
+	"errors"
+	"github.com/ok93-01-18/go-ms-lib/servers/http/internal/response"
+	"github.com/ok93-01-18/go-ms-lib/views"
+)
+
+// EmbedParam - parameter of layout template, which is replaced by the rendered template
+const EmbedParam = response.EmbedParam
+
+// ErrViewsNotSet - Context.Render is called, but the server has no views engine
+var ErrViewsNotSet = errors.New("views engine is not set, use Server.SetViews")
+
+// render renders the template, wraps it by layouts from inner to outer and sends it as HTML.
+func render(ctx Context, engine views.Engine, name string, params map[string]string, layouts []string) error {
+	if engine == nil {
+		return InternalServerError().Wrap(ErrViewsNotSet)
+	}
+
+	body, err := response.Render(engine, name, params, layouts)
+	if err != nil {
+		return InternalServerError().Wrap(err)
+	}
+
+	ctx.Set("Content-Type", response.HTMLContentType)
+	_, err = ctx.WriteString(body)
+	return err
+}
+
+// Offer - content type which handler can respond with and the function which sends it
+type Offer struct {
+	// Type - MIME type or file extension, e.g. "application/json" or "json".
+	Type string
+
+	// Send sends the response of the type.
+	Send func() error
+}
+
+// Negotiate calls Send of the offer which is the best for the Accept request header.
+// StatusNotAcceptable HTTPError is returned if no offer is acceptable.
+//
+//	return http.Negotiate(ctx,
+//	    http.Offer{Type: "application/json", Send: func() error { return ctx.JSON(user) }},
+//	    http.Offer{Type: "text/html", Send: func() error { return ctx.Render("user", params, "layout") }},
+//	)
+func Negotiate(ctx Context, offers ...Offer) error {
+	types := make([]string, 0, len(offers))
+	for _, offer := range offers {
+		types = append(types, offer.Type)
+	}
+
+	ctx.Append("Vary", "Accept")
+
+	accepted := ctx.Accepts(types...)
+	for _, offer := range offers {
+		if offer.Type == accepted {
+			return offer.Send()
+		}
+	}
+	return NewError(StatusNotAcceptable)
+}
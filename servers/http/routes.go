@@ -0,0 +1,169 @@
+package http
+
+import (
+	"fmt"
+	nethttp "net/http"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+)
+
+// allMethods - methods registered by All
+var allMethods = []string{
+	nethttp.MethodGet,
+	nethttp.MethodHead,
+	nethttp.MethodPost,
+	nethttp.MethodPut,
+	nethttp.MethodDelete,
+	nethttp.MethodConnect,
+	nethttp.MethodOptions,
+	nethttp.MethodTrace,
+	nethttp.MethodPatch,
+}
+
+// routeTable - routes registered through the server and its groups
+type routeTable struct {
+	sync.RWMutex
+	routes []*Route
+}
+
+// routeScope - routes of the table registered through the server or the group.
+// Name and Meta apply to the last route registered through the scope, list returns routes under its prefix.
+type routeScope struct {
+	table  *routeTable
+	prefix string
+
+	// last - routes registered by the last call, e.g. All registers route per method
+	last []*Route
+}
+
+// add adds routes of the path relative to the scope prefix.
+func (s *routeScope) add(path string, methods ...string) {
+	s.table.Lock()
+	defer s.table.Unlock()
+
+	path = joinPath(s.prefix, path)
+	if path == "" || path[0] != '/' {
+		path = "/" + path
+	}
+
+	s.last = make([]*Route, 0, len(methods))
+	for _, method := range methods {
+		route := &Route{Method: method, Path: path}
+		s.table.routes = append(s.table.routes, route)
+		s.last = append(s.last, route)
+	}
+}
+
+func (s *routeScope) name(name string) {
+	s.table.Lock()
+	defer s.table.Unlock()
+
+	for _, route := range s.last {
+		route.Name = name
+	}
+}
+
+func (s *routeScope) meta(key string, value interface{}) {
+	s.table.Lock()
+	defer s.table.Unlock()
+
+	for _, route := range s.last {
+		if route.Meta == nil {
+			route.Meta = make(map[string]interface{})
+		}
+		route.Meta[key] = value
+	}
+}
+
+// group returns scope of the group with the prefix relative to the scope prefix.
+func (s *routeScope) group(prefix string) *routeScope {
+	return newRouteScope(s.table, joinPath(s.prefix, prefix))
+}
+
+// list returns copies of routes under the scope prefix in order of registration.
+func (s *routeScope) list() []Route {
+	s.table.RLock()
+	defer s.table.RUnlock()
+
+	prefix := strings.TrimRight(s.prefix, "/")
+	routes := make([]Route, 0, len(s.table.routes))
+	for _, route := range s.table.routes {
+		if prefix != "" && route.Path != prefix && !strings.HasPrefix(route.Path, prefix+"/") {
+			continue
+		}
+
+		r := *route
+		if route.Meta != nil {
+			r.Meta = make(map[string]interface{}, len(route.Meta))
+			for key, value := range route.Meta {
+				r.Meta[key] = value
+			}
+		}
+		routes = append(routes, r)
+	}
+	return routes
+}
+
+// Match reports whether the route matches the request method and path the way servers do,
+// e.g. to find the route of the request before it reaches the handler. GET routes match HEAD requests.
+func (r Route) Match(method, path string) bool {
+	route := stdRoute{method: r.Method, segments: splitPath(r.Path)}
+	_, ok := route.match(method, splitPath(path))
+	return ok
+}
+
+func newRouteScope(table *routeTable, prefix string) *routeScope {
+	return &routeScope{table: table, prefix: prefix}
+}
+
+// joinPath joins group prefix and route path the way Fiber does, so route paths are the same for both servers.
+func joinPath(prefix, path string) string {
+	if path == "" || path == "/" {
+		return prefix
+	}
+	if path[0] != '/' {
+		path = "/" + path
+	}
+	return strings.TrimRight(prefix, "/") + path
+}
+
+// RoutesHandler - return handler which responds with the route table of the router sorted by path,
+// as JSON if the client accepts application/json or as plain text table otherwise.
+// It is intended for debugging, so register it on internal routes only.
+func RoutesHandler(router Router) Handler {
+	return func(ctx Context) error {
+		routes := router.Routes()
+		sort.SliceStable(routes, func(i, j int) bool {
+			return routes[i].Path < routes[j].Path
+		})
+
+		if strings.Contains(ctx.Get("Accept"), "application/json") {
+			return ctx.JSON(routes)
+		}
+
+		ctx.Set("Content-Type", "text/plain; charset=utf-8")
+		w := tabwriter.NewWriter(ctx, 0, 0, 2, ' ', 0)
+		_, _ = fmt.Fprintln(w, "METHOD\tPATH\tNAME\tMETA")
+		for _, route := range routes {
+			_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", route.Method, route.Path, route.Name, formatMeta(route.Meta))
+		}
+		return w.Flush()
+	}
+}
+
+// formatMeta formats metadata as space separated key=value pairs sorted by key.
+func formatMeta(meta map[string]interface{}) string {
+	keys := make([]string, 0, len(meta))
+	for key := range meta {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, key := range keys {
+		pairs = append(pairs, fmt.Sprintf("%s=%v", key, meta[key]))
+	}
+	return strings.Join(pairs, " ")
+}
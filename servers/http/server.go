@@ -1,7 +1,15 @@
 package http
 
 import (
+	"context"
+	"crypto/x509"
+	"github.com/ok93-01-18/go-ms-lib/servers/http/internal/response"
+	"github.com/ok93-01-18/go-ms-lib/views"
+	"io"
+	"mime/multipart"
 	"net"
+	nethttp "net/http"
+	"time"
 )
 
 // Server - http server
@@ -14,6 +22,19 @@ type Server interface {
 	// Listener can be used to pass a custom listener.
 	Listener(net.Listener) error
 
+	// ListenTLS serves HTTPS requests from the given addr with the certificate and key files.
+	// Files are reloaded by CertReloader when they change, so certificates are rotated without restart.
+	ListenTLS(addr, certFile, keyFile string) error
+
+	// ListenMutualTLS works like ListenTLS, but requires client certificates verified by CAs of clientCAFile,
+	// which is reloaded with the certificate. Verified certificate is returned by Context.ClientCertificate.
+	ListenMutualTLS(addr, certFile, keyFile, clientCAFile string) error
+
+	// ListenTLSReloader serves HTTPS requests from the given addr with certificates of the reloader,
+	// e.g. to log failed reloads by CertReloaderConfig.Logger, which ListenTLS and ListenMutualTLS don't do.
+	// The reloader is not closed when the server stops.
+	ListenTLSReloader(addr string, reloader *CertReloader) error
+
 	// Shutdown gracefully shuts down the server without interrupting any active connections.
 	// Shutdown works by first closing all open listeners and then waiting indefinitely for all connections to return to idle and then shut down.
 	//
@@ -21,6 +42,42 @@ type Server interface {
 	//
 	// Shutdown does not close keepalive connections so its recommended to set ReadTimeout to something else than 0.
 	Shutdown() error
+
+	// ShutdownWithContext works like Shutdown but stops waiting for active connections when the context is done
+	// and returns the context error. Shutdown hooks are executed afterwards with the same context.
+	//
+	// Event streams are closed when shutdown starts. Websocket connections are sent the close message
+	// with CloseGoingAway after the server stopped to serve, they are closed forcibly if their handlers
+	// don't return until the context is done.
+	ShutdownWithContext(context.Context) error
+
+	// ShutdownWithTimeout works like ShutdownWithContext with a context which is done after the given timeout.
+	ShutdownWithTimeout(time.Duration) error
+
+	// OnStartup registers hooks which are executed in registration order before the server starts to serve.
+	// If any hook returns an error, the server is not started and the error is returned by Listen.
+	OnStartup(...StartupHook)
+
+	// OnShutdown registers hooks which are executed in registration order after the server stopped to serve,
+	// so dependencies can be drained in a defined order:
+	//  server.OnShutdown(cronManager.Stop, func(context.Context) error {
+	//      logger.Close()
+	//      return nil
+	//  })
+	OnShutdown(...ShutdownHook)
+
+	// SetErrorHandler sets the handler of errors returned by handlers instead of the default one,
+	// which responds with the status of HTTPError and its message as plain text.
+	// NewErrorHandler returns the handler which renders errors consistently as JSON.
+	SetErrorHandler(ErrorHandler)
+
+	// SetViews sets the engine of templates rendered by Context.Render.
+	SetViews(views.Engine)
+
+	// Test handles the request in-process without listening and returns the response,
+	// so handlers can be tested in parallel without binding ports.
+	// Error is returned if the request is not handled within the timeout, timeout <= 0 disables it.
+	Test(req *nethttp.Request, timeout time.Duration) (*nethttp.Response, error)
 }
 
 type Router interface {
@@ -44,15 +101,67 @@ type Router interface {
 	// Delete registers a route for DELETE methods that deletes the specified resource.
 	Delete(string, ...Handler) Router
 
+	// Put registers a route for PUT methods that replaces all current representations
+	// of the target resource with the request payload.
+	Put(string, ...Handler) Router
+
+	// Patch registers a route for PATCH methods that is used to apply partial
+	// modifications to a resource.
+	Patch(string, ...Handler) Router
+
+	// Connect registers a route for CONNECT methods that establishes a tunnel to the
+	// server identified by the target resource.
+	Connect(string, ...Handler) Router
+
+	// Trace registers a route for TRACE methods that performs a message loop-back
+	// test along the path to the target resource.
+	Trace(string, ...Handler) Router
+
+	// All registers a route for all HTTP methods.
+	All(string, ...Handler) Router
+
+	// Add allows you to specify a HTTP method to register a route.
+	Add(string, string, ...Handler) Router
+
 	// Use registers a middleware route that will match requests
 	// with the provided prefix (which is optional and defaults to "/").
 	// This method will match all HTTP verbs: GET, POST, PUT, HEAD etc...
 	Use(...interface{}) Router
 
+	// WebSocket registers a GET route which upgrades requests to the websocket protocol and runs the handler
+	// on the connection. Requests without upgrade headers are responded with StatusUpgradeRequired.
+	// Origin of the request is not checked, use middleware to restrict it.
+	//  app.WebSocket("/ws/:room", func(conn http.Conn) error {
+	//      for {
+	//          messageType, data, err := conn.ReadMessage()
+	//          if err != nil {
+	//              return err
+	//          }
+	//          err = conn.WriteMessage(messageType, data)
+	//          if err != nil {
+	//              return err
+	//          }
+	//      }
+	//  })
+	WebSocket(string, WebSocketHandler) Router
+
 	// Group is used for Routes with common prefix to define a new sub-router with optional middleware.
 	//  api := app.Group("/api")
 	//  api.Get("/users", handler)
 	Group(string, ...Handler) Router
+
+	// Name sets the name of the last route registered through the router.
+	//  app.Get("/users/:id", handler).Name("user")
+	Name(string) Router
+
+	// Meta sets metadata of the last route registered through the router by key, e.g. for documentation.
+	//  app.Get("/users/:id", handler).Meta("summary", "Get user")
+	Meta(string, interface{}) Router
+
+	// Routes returns routes registered through the server and its groups in order of registration,
+	// routes of the group are limited to ones under its prefix.
+	// Middleware is not included, route registered by Get is not duplicated for HEAD method.
+	Routes() []Route
 }
 
 // Context represents the Context which hold the HTTP request and response.
@@ -93,6 +202,18 @@ type Context interface {
 	// Request return Request interface struct of HTTP request
 	Request() Request
 
+	// Response return Response interface struct of HTTP response
+	Response() Response
+
+	// Context returns context.Context of the request, which is passed to calls made on its behalf.
+	// StdServer cancels it when the client disconnects, FiberApp returns the fiber user context,
+	// which is context.Background unless it is set by fiber middleware.
+	Context() context.Context
+
+	// Route returns method and path of the route of the current handler.
+	// After Next returns, it is the last route reached by the request.
+	Route() Route
+
 	// Writef appends f & a into response body writer.
 	Writef(string, ...interface{}) (int, error)
 
@@ -105,6 +226,18 @@ type Context interface {
 	// If none of the content types above are matched, it will return a ErrUnprocessableEntity error
 	BodyParser(interface{}) error
 
+	// BindQuery binds the query string parameters to a struct by "query" tag and validates it by ValidateTag rules.
+	// Decoding errors and rule violations are returned as StatusUnprocessableEntity HTTPError with []FieldViolation details.
+	// Slices accept repeated and comma-separated values, time.Time accepts RFC 3339 and "2006-01-02" formats.
+	BindQuery(interface{}) error
+
+	// BindParams binds the route parameters to a struct by "params" tag and validates it like BindQuery.
+	BindParams(interface{}) error
+
+	// BindHeaders binds the request headers to a struct by "header" tag and validates it like BindQuery.
+	// Header names are case-insensitive.
+	BindHeaders(interface{}) error
+
 	// Next executes the next method in the stack that matches the current route.
 	Next() error
 
@@ -131,6 +264,68 @@ type Context interface {
 	// Returned value is only valid within the handler. Do not store any references.
 	// Make copies or use the Immutable setting to use the value outside the Handler.
 	Params(key string, defaultValue ...string) string
+
+	// JSON converts any interface or string to JSON and sets it as the response body.
+	// This method also sets the content header to application/json.
+	JSON(interface{}) error
+
+	// XML converts any interface to XML and sets it as the response body.
+	// This method also sets the content header to application/xml.
+	XML(interface{}) error
+
+	// SendFile transfers the file from the given path.
+	// The file is not compressed by default, enable this by passing a 'true' argument,
+	// backends which can't compress files ignore it.
+	// Sets the Content-Type response HTTP header field based on the filenames extension.
+	SendFile(string, ...bool) error
+
+	// SendStream sets response body stream and optional body size.
+	SendStream(io.Reader, ...int) error
+
+	// Cookie sets a cookie by passing a cookie struct.
+	Cookie(*Cookie)
+
+	// ClearCookie expires a specific cookie by key on the client side.
+	// If no key is provided it expires all cookies that came with the request.
+	ClearCookie(...string)
+
+	// Type sets the Content-Type HTTP header to the MIME type specified by the file extension.
+	// This method is chainable.
+	Type(string, ...string) Context
+
+	// SendStatus sets the HTTP status code and if the response body is empty,
+	// it sets the correct status message in the body.
+	SendStatus(int) error
+
+	// Render renders the template of the server views engine with params and sends it as text/html.
+	// Each layout is rendered with the same params and the result of previous rendering as EmbedParam.
+	//  ctx.Render("index", map[string]string{"{{title}}": "Home"}, "layout")
+	Render(name string, params map[string]string, layouts ...string) error
+
+	// SSE responds with the stream of server-sent events written by the handler.
+	// Heartbeat comments are sent at the given interval, DefaultHeartbeatInterval by default, <= 0 disables them,
+	// so disconnected clients are detected even if no events are sent.
+	// Servers of NewFiberServer detect disconnected clients only by failed writes, so Done isn't closed until the next write.
+	// The error returned by the handler only ends the stream, because the response is already sent.
+	// Context must not be used by the handler, it can be run after SSE returns.
+	//  ctx.SSE(func(stream http.EventStream) error {
+	//      for progress := range job.Progress(stream.LastEventID()) {
+	//          err := stream.Send(http.Event{ID: progress.ID, Data: progress.Message})
+	//          if err != nil {
+	//              return err
+	//          }
+	//      }
+	//      return nil
+	//  })
+	SSE(EventStreamHandler, ...time.Duration) error
+
+	// ClientCertificate returns the verified client certificate of the TLS connection,
+	// nil is returned for connections without TLS or client certificate.
+	ClientCertificate() *x509.Certificate
+
+	// Accepts returns the offer which is the best for the Accept request header, offers are MIME types or file extensions.
+	// The first offer is returned if the header is empty and empty string if no offer is acceptable.
+	Accepts(...string) string
 }
 
 // Request - HTTP request
@@ -143,6 +338,92 @@ type Request interface {
 
 	// RequestURI returns request's URI.
 	RequestURI() string
+
+	// Cookies is used for getting a cookie value by key.
+	// Defaults to the empty string "" if the cookie doesn't exist.
+	// If a default value is given, it will return that value if the cookie doesn't exist.
+	// Returned value is only valid within the handler. Do not store any references.
+	Cookies(string, ...string) string
+
+	// FormValue returns the first value by key from a query string, an url-encoded or a multipart form.
+	// Defaults to the empty string "" if the value doesn't exist.
+	// If a default value is given, it will return that value if the value doesn't exist.
+	// Returned value is only valid within the handler. Do not store any references.
+	FormValue(string, ...string) string
+
+	// MultipartForm parses the request body as multipart/form-data and returns the form.
+	MultipartForm() (*multipart.Form, error)
+
+	// FormFile returns the first file by key from a multipart form.
+	FormFile(string) (*multipart.FileHeader, error)
+
+	// Path returns the path part of the request URL.
+	// Returned value is only valid within the handler. Do not store any references.
+	Path() string
+
+	// QueryString returns the raw query string of the request URL without the leading '?'.
+	QueryString() string
+
+	// Scheme returns the request scheme: "http" or "https" for TLS requests.
+	Scheme() string
+
+	// Protocol returns the request protocol version, e.g. "HTTP/1.1".
+	Protocol() string
+
+	// VisitHeaders calls f for each request header.
+	// Header with multiple values is visited once per value.
+	VisitHeaders(f func(key, value string))
+}
+
+// Response - HTTP response
+type Response interface {
+	// StatusCode returns the response status code.
+	StatusCode() int
+
+	// Body returns the response body.
+	// Body stream is read entirely, so use BodySize to get the size of streamed body.
+	Body() []byte
+
+	// BodySize returns the response body size without reading the body stream,
+	// -1 is returned when the size of the body stream is unknown.
+	BodySize() int
+
+	// Header returns the response header value by key.
+	Header(string) string
+
+	// VisitHeaders calls f for each response header.
+	// Header with multiple values is visited once per value.
+	VisitHeaders(f func(key, value string))
+
+	// Reset discards the status, headers and body set so far, e.g. to respond with an error instead.
+	// Response which has already been sent to the client is not affected.
+	Reset()
+}
+
+// Cookie - data for Set-Cookie response header
+type Cookie = response.Cookie
+
+// Cookie SameSite values, lax mode is used when SameSite is empty
+const (
+	CookieSameSiteDisabled   = response.CookieSameSiteDisabled
+	CookieSameSiteLaxMode    = response.CookieSameSiteLaxMode
+	CookieSameSiteStrictMode = response.CookieSameSiteStrictMode
+	CookieSameSiteNoneMode   = response.CookieSameSiteNoneMode
+)
+
+// Route - registered route
+type Route struct {
+	// Method - HTTP method of the request matched by the route
+	Method string `json:"method"`
+
+	// Path - route path including group prefixes, e.g. "/api/users/:id"
+	Path string `json:"path"`
+
+	// Name - route name set by Router.Name
+	Name string `json:"name,omitempty"`
+
+	// Meta - route metadata set by Router.Meta
+	Meta map[string]interface{} `json:"meta,omitempty"`
 }
 
 // Handler - handler of http request
@@ -0,0 +1,546 @@
+package http_test
+
+import (
+	"context"
+	"errors"
+	"github.com/ok93-01-18/go-ms-lib/servers/http"
+	"github.com/ok93-01-18/go-ms-lib/servers/http/httpassert"
+	"github.com/ok93-01-18/go-ms-lib/servers/http/internal/servertest"
+	nethttp "net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestServer_Methods(t *testing.T) {
+	servertest.Run(t, func(t *testing.T, server http.Server) {
+		echo := func(ctx http.Context) error {
+			_, err := ctx.WriteString(ctx.Method())
+			return err
+		}
+		server.Get("/get", echo)
+		server.Post("/post", echo)
+		server.Put("/put", echo)
+		server.Patch("/patch", echo)
+		server.Delete("/delete", echo)
+		server.Options("/options", echo)
+		server.Add(nethttp.MethodPost, "/add", echo)
+		server.All("/all", echo)
+
+		for _, method := range []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"} {
+			path := "/" + strings.ToLower(method)
+			httpassert.Do(t, server, httpassert.NewRequest(method, path, nil)).
+				Status(http.StatusOK).
+				Body(method)
+			httpassert.Do(t, server, httpassert.NewRequest(method, "/all", nil)).
+				Status(http.StatusOK).
+				Body(method)
+		}
+		httpassert.Do(t, server, httpassert.NewRequest(nethttp.MethodPost, "/add", nil)).
+			Status(http.StatusOK).
+			Body("POST")
+		httpassert.Do(t, server, httpassert.NewRequest(nethttp.MethodHead, "/get", nil)).
+			Status(http.StatusOK).
+			Body("")
+	})
+}
+
+func TestServer_NotFound(t *testing.T) {
+	servertest.Run(t, func(t *testing.T, server http.Server) {
+		server.Get("/users", func(ctx http.Context) error {
+			return ctx.SendStatus(http.StatusOK)
+		})
+
+		httpassert.Do(t, server, httpassert.NewRequest(nethttp.MethodGet, "/missing", nil)).
+			Status(http.StatusNotFound)
+		httpassert.Do(t, server, httpassert.NewRequest(nethttp.MethodGet, "/users/1", nil)).
+			Status(http.StatusNotFound)
+	})
+}
+
+func TestServer_MethodNotAllowed(t *testing.T) {
+	servertest.Run(t, func(t *testing.T, server http.Server) {
+		server.Get("/users", func(ctx http.Context) error {
+			return ctx.SendStatus(http.StatusOK)
+		})
+
+		httpassert.Do(t, server, httpassert.NewRequest(nethttp.MethodPost, "/users", nil)).
+			Status(http.StatusMethodNotAllowed)
+	})
+}
+
+func TestServer_Params(t *testing.T) {
+	servertest.Run(t, func(t *testing.T, server http.Server) {
+		server.Get("/users/:id/posts/:post?", func(ctx http.Context) error {
+			_, err := ctx.Writef("%s|%s", ctx.Params("id"), ctx.Params("post", "none"))
+			return err
+		})
+		server.Get("/files/*", func(ctx http.Context) error {
+			_, err := ctx.WriteString(ctx.Params("*"))
+			return err
+		})
+
+		httpassert.Do(t, server, httpassert.NewRequest(nethttp.MethodGet, "/users/7/posts/3", nil)).
+			Status(http.StatusOK).
+			Body("7|3")
+		httpassert.Do(t, server, httpassert.NewRequest(nethttp.MethodGet, "/users/7/posts", nil)).
+			Status(http.StatusOK).
+			Body("7|none")
+		httpassert.Do(t, server, httpassert.NewRequest(nethttp.MethodGet, "/files/a/b.txt", nil)).
+			Status(http.StatusOK).
+			Body("a/b.txt")
+	})
+}
+
+func TestServer_Query(t *testing.T) {
+	servertest.Run(t, func(t *testing.T, server http.Server) {
+		server.Get("/search", func(ctx http.Context) error {
+			_, err := ctx.Writef("%s|%s|%s", ctx.Query("q"), ctx.Query("page", "1"), ctx.Request().QueryString())
+			return err
+		})
+
+		httpassert.Do(t, server, httpassert.NewRequest(nethttp.MethodGet, "/search?q=go+lang", nil)).
+			Status(http.StatusOK).
+			Body("go lang|1|q=go+lang")
+	})
+}
+
+func TestServer_Request(t *testing.T) {
+	servertest.Run(t, func(t *testing.T, server http.Server) {
+		server.Post("/request", func(ctx http.Context) error {
+			req := ctx.Request()
+			_, err := ctx.Writef("%s|%s|%s|%s|%s|%d|%s|%s",
+				req.Path(), req.RequestURI(), req.Scheme(), req.Protocol(), req.Body(), req.GetContentLength(),
+				ctx.Get("X-Custom"), ctx.Get("X-Missing", "default"))
+			return err
+		})
+
+		req := httpassert.NewRequest(nethttp.MethodPost, "/request?a=1", "body")
+		req.Header.Set("X-Custom", "value")
+		httpassert.Do(t, server, req).
+			Status(http.StatusOK).
+			Body("/request|/request?a=1|http|HTTP/1.1|body|4|value|default")
+	})
+}
+
+func TestServer_Middleware(t *testing.T) {
+	servertest.Run(t, func(t *testing.T, server http.Server) {
+		var order []string
+		server.Use(func(ctx http.Context) error {
+			order = append(order, "global")
+			ctx.Locals("user", "john")
+			err := ctx.Next()
+			order = append(order, "global after")
+			return err
+		})
+		server.Use("/api", func(ctx http.Context) error {
+			order = append(order, "api")
+			return ctx.Next()
+		})
+		server.Get("/api/users", func(ctx http.Context) error {
+			order = append(order, "handler")
+			_, err := ctx.Writef("%v", ctx.Locals("user"))
+			return err
+		})
+
+		httpassert.Do(t, server, httpassert.NewRequest(nethttp.MethodGet, "/api/users", nil)).
+			Status(http.StatusOK).
+			Body("john")
+
+		want := []string{"global", "api", "handler", "global after"}
+		if !reflect.DeepEqual(order, want) {
+			t.Errorf("order = %v, want %v", order, want)
+		}
+	})
+}
+
+func TestServer_Group(t *testing.T) {
+	servertest.Run(t, func(t *testing.T, server http.Server) {
+		api := server.Group("/api", func(ctx http.Context) error {
+			ctx.Set("X-Group", "api")
+			return ctx.Next()
+		})
+		v1 := api.Group("/v1")
+		v1.Get("/users/:id", func(ctx http.Context) error {
+			_, err := ctx.WriteString(ctx.Params("id"))
+			return err
+		})
+		server.Get("/health", func(ctx http.Context) error {
+			return ctx.SendStatus(http.StatusOK)
+		})
+
+		httpassert.Do(t, server, httpassert.NewRequest(nethttp.MethodGet, "/api/v1/users/5", nil)).
+			Status(http.StatusOK).
+			Header("X-Group", "api").
+			Body("5")
+		httpassert.Do(t, server, httpassert.NewRequest(nethttp.MethodGet, "/health", nil)).
+			Status(http.StatusOK).
+			Header("X-Group", "")
+	})
+}
+
+func TestServer_Routes(t *testing.T) {
+	servertest.Run(t, func(t *testing.T, server http.Server) {
+		handler := func(ctx http.Context) error {
+			return ctx.SendStatus(http.StatusOK)
+		}
+		server.Use(handler)
+		server.Get("/users", handler).Name("users").Meta("summary", "List users")
+		server.Group("/api").Post("/items", handler).Name("items")
+
+		want := []http.Route{
+			{Method: nethttp.MethodGet, Path: "/users", Name: "users", Meta: map[string]interface{}{"summary": "List users"}},
+			{Method: nethttp.MethodPost, Path: "/api/items", Name: "items"},
+		}
+		if got := server.Routes(); !reflect.DeepEqual(got, want) {
+			t.Errorf("Routes() = %+v, want %+v", got, want)
+		}
+	})
+}
+
+func TestServer_Route(t *testing.T) {
+	servertest.Run(t, func(t *testing.T, server http.Server) {
+		server.Get("/users/:id", func(ctx http.Context) error {
+			route := ctx.Route()
+			_, err := ctx.WriteString(route.Method + " " + route.Path)
+			return err
+		})
+
+		httpassert.Do(t, server, httpassert.NewRequest(nethttp.MethodGet, "/users/1", nil)).
+			Status(http.StatusOK).
+			Body("GET /users/:id")
+	})
+}
+
+func TestRoute_Match(t *testing.T) {
+	for _, test := range []struct {
+		route  http.Route
+		method string
+		path   string
+		want   bool
+	}{
+		{route: http.Route{Method: nethttp.MethodGet, Path: "/users/:id"}, method: nethttp.MethodGet, path: "/users/1", want: true},
+		{route: http.Route{Method: nethttp.MethodGet, Path: "/users/:id"}, method: nethttp.MethodHead, path: "/users/1", want: true},
+		{route: http.Route{Method: nethttp.MethodGet, Path: "/users/:id"}, method: nethttp.MethodPost, path: "/users/1"},
+		{route: http.Route{Method: nethttp.MethodGet, Path: "/users/:id"}, method: nethttp.MethodGet, path: "/users"},
+		{route: http.Route{Method: nethttp.MethodGet, Path: "/users/:id"}, method: nethttp.MethodGet, path: "/users/1/items"},
+		{route: http.Route{Method: nethttp.MethodGet, Path: "/users/:id?"}, method: nethttp.MethodGet, path: "/users", want: true},
+		{route: http.Route{Method: nethttp.MethodGet, Path: "/files/*"}, method: nethttp.MethodGet, path: "/files/a/b", want: true},
+	} {
+		if got := test.route.Match(test.method, test.path); got != test.want {
+			t.Errorf("%s %s Match(%s, %s) = %t, want %t", test.route.Method, test.route.Path, test.method, test.path, got, test.want)
+		}
+	}
+}
+
+func TestServer_Headers(t *testing.T) {
+	servertest.Run(t, func(t *testing.T, server http.Server) {
+		server.Get("/headers", func(ctx http.Context) error {
+			ctx.Set("X-Single", "one")
+			ctx.Append("Vary", "Origin")
+			ctx.Append("Vary", "Accept")
+			_, err := ctx.WriteString(ctx.GetReqHeaders()["X-Request"])
+			return err
+		})
+
+		req := httpassert.NewRequest(nethttp.MethodGet, "/headers", nil)
+		req.Header.Set("X-Request", "value")
+		httpassert.Do(t, server, req).
+			Status(http.StatusOK).
+			Header("X-Single", "one").
+			Header("Vary", "Origin, Accept").
+			Body("value")
+	})
+}
+
+func TestServer_Responses(t *testing.T) {
+	type user struct {
+		Name string `json:"name" xml:"name"`
+	}
+
+	servertest.Run(t, func(t *testing.T, server http.Server) {
+		server.Get("/json", func(ctx http.Context) error {
+			return ctx.Status(http.StatusCreated).JSON(&user{Name: "John"})
+		})
+		server.Get("/xml", func(ctx http.Context) error {
+			return ctx.XML(&user{Name: "John"})
+		})
+		server.Get("/status", func(ctx http.Context) error {
+			return ctx.SendStatus(http.StatusAccepted)
+		})
+		server.Get("/redirect", func(ctx http.Context) error {
+			return ctx.Redirect("/json", http.StatusMovedPermanently)
+		})
+		server.Get("/type", func(ctx http.Context) error {
+			ctx.Type("txt")
+			_, err := ctx.WriteString("text")
+			return err
+		})
+		server.Get("/stream", func(ctx http.Context) error {
+			return ctx.SendStream(strings.NewReader("streamed"))
+		})
+
+		httpassert.Do(t, server, httpassert.NewRequest(nethttp.MethodGet, "/json", nil)).
+			Status(http.StatusCreated).
+			Header("Content-Type", "application/json").
+			JSON(`{"name":"John"}`)
+		httpassert.Do(t, server, httpassert.NewRequest(nethttp.MethodGet, "/xml", nil)).
+			Status(http.StatusOK).
+			Header("Content-Type", "application/xml").
+			Body("<user><name>John</name></user>")
+		httpassert.Do(t, server, httpassert.NewRequest(nethttp.MethodGet, "/status", nil)).
+			Status(http.StatusAccepted).
+			Body("Accepted")
+		httpassert.Do(t, server, httpassert.NewRequest(nethttp.MethodGet, "/redirect", nil)).
+			Status(http.StatusMovedPermanently).
+			Header("Location", "/json")
+		httpassert.Do(t, server, httpassert.NewRequest(nethttp.MethodGet, "/type", nil)).
+			Status(http.StatusOK).
+			Header("Content-Type", "text/plain").
+			Body("text")
+		httpassert.Do(t, server, httpassert.NewRequest(nethttp.MethodGet, "/stream", nil)).
+			Status(http.StatusOK).
+			Body("streamed")
+	})
+}
+
+func TestServer_SendFile(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "index.html")
+	err := os.WriteFile(file, []byte("<h1>index</h1>"), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	servertest.Run(t, func(t *testing.T, server http.Server) {
+		server.Get("/index", func(ctx http.Context) error {
+			return ctx.SendFile(file)
+		})
+		server.Get("/missing", func(ctx http.Context) error {
+			return ctx.SendFile(file + ".missing")
+		})
+
+		httpassert.Do(t, server, httpassert.NewRequest(nethttp.MethodGet, "/index", nil)).
+			Status(http.StatusOK).
+			Header("Content-Type", "text/html; charset=utf-8").
+			Body("<h1>index</h1>")
+		httpassert.Do(t, server, httpassert.NewRequest(nethttp.MethodGet, "/missing", nil)).
+			Status(http.StatusNotFound)
+	})
+}
+
+func TestServer_Cookies(t *testing.T) {
+	servertest.Run(t, func(t *testing.T, server http.Server) {
+		server.Get("/cookies", func(ctx http.Context) error {
+			ctx.Cookie(&http.Cookie{Name: "session", Value: "new", Path: "/"})
+			_, err := ctx.WriteString(ctx.Request().Cookies("session") + "|" + ctx.Request().Cookies("missing", "none"))
+			return err
+		})
+
+		req := httpassert.NewRequest(nethttp.MethodGet, "/cookies", nil)
+		req.AddCookie(&nethttp.Cookie{Name: "session", Value: "old"})
+		resp := httpassert.Do(t, server, req).
+			Status(http.StatusOK).
+			Body("old|none")
+
+		cookies := resp.Cookies()
+		if len(cookies) != 1 || cookies[0].Name != "session" || cookies[0].Value != "new" {
+			t.Errorf("cookies = %v, want session=new", cookies)
+		}
+	})
+}
+
+func TestServer_BodyParser(t *testing.T) {
+	type user struct {
+		Name string `json:"name" xml:"name" form:"name"`
+		Age  int    `json:"age" xml:"age" form:"age"`
+	}
+
+	servertest.Run(t, func(t *testing.T, server http.Server) {
+		server.Post("/users", func(ctx http.Context) error {
+			var u user
+			err := ctx.BodyParser(&u)
+			if err != nil {
+				return err
+			}
+			_, err = ctx.Writef("%s|%d", u.Name, u.Age)
+			return err
+		})
+
+		httpassert.Do(t, server, httpassert.NewRequest(nethttp.MethodPost, "/users", &user{Name: "John", Age: 30})).
+			Status(http.StatusOK).
+			Body("John|30")
+
+		req := httpassert.NewRequest(nethttp.MethodPost, "/users", url.Values{"name": {"Jane"}, "age": {"25"}}.Encode())
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		httpassert.Do(t, server, req).
+			Status(http.StatusOK).
+			Body("Jane|25")
+
+		req = httpassert.NewRequest(nethttp.MethodPost, "/users", "<user><name>Joe</name><age>40</age></user>")
+		req.Header.Set("Content-Type", "application/xml")
+		httpassert.Do(t, server, req).
+			Status(http.StatusOK).
+			Body("Joe|40")
+
+		req = httpassert.NewRequest(nethttp.MethodPost, "/users", "name")
+		req.Header.Set("Content-Type", "text/plain")
+		httpassert.Do(t, server, req).
+			Status(http.StatusUnprocessableEntity)
+	})
+}
+
+func TestServer_DefaultErrorHandler(t *testing.T) {
+	servertest.Run(t, func(t *testing.T, server http.Server) {
+		server.Get("/conflict", func(ctx http.Context) error {
+			return http.Conflict("user exists")
+		})
+		server.Get("/internal", func(ctx http.Context) error {
+			return errors.New("database is down")
+		})
+
+		httpassert.Do(t, server, httpassert.NewRequest(nethttp.MethodGet, "/conflict", nil)).
+			Status(http.StatusConflict).
+			Body("user exists")
+		httpassert.Do(t, server, httpassert.NewRequest(nethttp.MethodGet, "/internal", nil)).
+			Status(http.StatusInternalServerError).
+			Body("database is down")
+	})
+}
+
+func TestServer_ErrorHandler(t *testing.T) {
+	servertest.Run(t, func(t *testing.T, server http.Server) {
+		server.SetErrorHandler(http.NewErrorHandler(&http.ErrorHandlerConfig{}))
+		server.Get("/conflict", func(ctx http.Context) error {
+			return http.Conflict("user exists").WithCode("user_exists")
+		})
+
+		httpassert.Do(t, server, httpassert.NewRequest(nethttp.MethodGet, "/conflict", nil)).
+			Status(http.StatusConflict).
+			Header("Content-Type", "application/json").
+			JSON(`{"status":409,"code":"user_exists","message":"user exists"}`)
+		httpassert.Do(t, server, httpassert.NewRequest(nethttp.MethodGet, "/missing", nil)).
+			Status(http.StatusNotFound).
+			JSON(`{"status":404,"message":"Cannot GET /missing"}`)
+	})
+}
+
+func TestServer_Accepts(t *testing.T) {
+	servertest.Run(t, func(t *testing.T, server http.Server) {
+		server.Get("/accepts", func(ctx http.Context) error {
+			_, err := ctx.WriteString(ctx.Accepts("json", "html"))
+			return err
+		})
+
+		req := httpassert.NewRequest(nethttp.MethodGet, "/accepts", nil)
+		req.Header.Set("Accept", "text/html;q=0.9, application/json;q=0.5")
+		httpassert.Do(t, server, req).
+			Status(http.StatusOK).
+			Body("html")
+	})
+}
+
+func TestServer_StartupHookError(t *testing.T) {
+	servertest.Run(t, func(t *testing.T, server http.Server) {
+		hookErr := errors.New("migration failed")
+		server.OnStartup(func() error {
+			return hookErr
+		})
+
+		err := server.Listen("127.0.0.1:0")
+		if !errors.Is(err, hookErr) {
+			t.Errorf("Listen() = %v, want %v", err, hookErr)
+		}
+	})
+}
+
+func TestServer_ShutdownHooks(t *testing.T) {
+	servertest.Run(t, func(t *testing.T, server http.Server) {
+		var order []string
+		server.OnShutdown(func(context.Context) error {
+			order = append(order, "first")
+			return nil
+		}, func(context.Context) error {
+			order = append(order, "second")
+			return nil
+		})
+
+		server.Get("/health", func(ctx http.Context) error {
+			return ctx.SendStatus(http.StatusOK)
+		})
+
+		// the response ensures the server serves before it is shut down
+		addr := servertest.Serve(t, server)
+		resp, err := nethttp.Get("http://" + addr + "/health")
+		if err != nil {
+			t.Fatal(err)
+		}
+		_ = resp.Body.Close()
+
+		err = server.Shutdown()
+		if err != nil {
+			t.Fatalf("Shutdown() = %v", err)
+		}
+
+		want := []string{"first", "second"}
+		if !reflect.DeepEqual(order, want) {
+			t.Errorf("order = %v, want %v", order, want)
+		}
+	})
+}
+
+func TestServer_NextError(t *testing.T) {
+	servertest.Run(t, func(t *testing.T, server http.Server) {
+		errFailed := errors.New("failed")
+		server.Use(func(ctx http.Context) error {
+			err := ctx.Next()
+			switch {
+			case errors.Is(err, errFailed):
+				_, err = ctx.Status(http.StatusTeapot).WriteString("next failed")
+			case err != nil:
+				_, err = ctx.Status(http.StatusAccepted).WriteString(strconv.Itoa(http.ErrorStatusCode(err)))
+			}
+			return err
+		})
+		server.Get("/failed", func(ctx http.Context) error {
+			return errFailed
+		})
+
+		httpassert.Do(t, server, httpassert.NewRequest(nethttp.MethodGet, "/failed", nil)).
+			Status(http.StatusTeapot).
+			Body("next failed")
+		httpassert.Do(t, server, httpassert.NewRequest(nethttp.MethodGet, "/missing", nil)).
+			Status(http.StatusAccepted).
+			Body("404")
+	})
+}
+
+func TestServer_GroupRoutes(t *testing.T) {
+	servertest.Run(t, func(t *testing.T, server http.Server) {
+		handler := func(ctx http.Context) error {
+			return ctx.SendStatus(http.StatusOK)
+		}
+		api := server.Group("/api")
+		server.Get("/users", handler)
+		api.Get("/items", handler)
+		server.Name("users")
+		api.Name("items").Meta("summary", "List items")
+		api.Group("/v1").Post("/orders", handler).Name("orders")
+
+		want := []http.Route{
+			{Method: nethttp.MethodGet, Path: "/api/items", Name: "items", Meta: map[string]interface{}{"summary": "List items"}},
+			{Method: nethttp.MethodPost, Path: "/api/v1/orders", Name: "orders"},
+		}
+		if got := api.Routes(); !reflect.DeepEqual(got, want) {
+			t.Errorf("group Routes() = %+v, want %+v", got, want)
+		}
+
+		want = append([]http.Route{{Method: nethttp.MethodGet, Path: "/users", Name: "users"}}, want...)
+		if got := server.Routes(); !reflect.DeepEqual(got, want) {
+			t.Errorf("Routes() = %+v, want %+v", got, want)
+		}
+	})
+}
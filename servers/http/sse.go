@@ -0,0 +1,282 @@
+package http
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultHeartbeatInterval - interval of heartbeat comments of event streams
+const DefaultHeartbeatInterval = 15 * time.Second
+
+var (
+	// ErrStreamClosed - returned by writes to the event stream after the client disconnected or the server shut down
+	ErrStreamClosed = errors.New("sse: stream closed")
+
+	// errEventField - event ID or type contains line breaks, so it can't be written
+	errEventField = errors.New("sse: event id and type must not contain line breaks")
+)
+
+// Event - server-sent event
+type Event struct {
+	// ID - event ID, which is sent back by the client in Last-Event-ID header on reconnect
+	ID string
+
+	// Event - event type, clients dispatch events without type as "message"
+	Event string
+
+	// Data - event data, each line is sent as separate data field
+	Data string
+
+	// Retry - reconnection time hint for the client, it is not sent when zero
+	Retry time.Duration
+}
+
+// WriteTo writes the event in text/event-stream format.
+func (e *Event) WriteTo(w io.Writer) (int64, error) {
+	if strings.ContainsAny(e.ID, "\r\n") || strings.ContainsAny(e.Event, "\r\n") {
+		return 0, errEventField
+	}
+
+	var buf bytes.Buffer
+	if e.ID != "" {
+		buf.WriteString("id: " + e.ID + "\n")
+	}
+	if e.Event != "" {
+		buf.WriteString("event: " + e.Event + "\n")
+	}
+	if e.Retry > 0 {
+		buf.WriteString("retry: " + strconv.FormatInt(e.Retry.Milliseconds(), 10) + "\n")
+	}
+	if e.Data != "" {
+		for _, line := range splitLines(e.Data) {
+			buf.WriteString("data: " + line + "\n")
+		}
+	}
+	buf.WriteByte('\n')
+
+	return buf.WriteTo(w)
+}
+
+// EventStream - stream of server-sent events of the response
+type EventStream interface {
+	// Send writes the event and flushes it to the client.
+	Send(Event) error
+
+	// Comment writes the comment, which is ignored by clients, and flushes it to the client.
+	Comment(string) error
+
+	// LastEventID returns the Last-Event-ID request header sent by reconnecting clients,
+	// so the stream can be resumed after the last received event.
+	LastEventID() string
+
+	// Done returns the channel, which is closed when the client disconnected or the server shuts down.
+	// Writes return error afterwards.
+	Done() <-chan struct{}
+}
+
+// EventStreamHandler - function writing events to the stream, the response ends when it returns
+type EventStreamHandler func(EventStream) error
+
+// eventStream - EventStream writing to the response body
+type eventStream struct {
+	mu          sync.Mutex
+	writer      io.Writer
+	flush       func() error
+	lastEventID string
+	err         error
+	done        chan struct{}
+}
+
+func (s *eventStream) Send(event Event) error {
+	return s.write(&event)
+}
+
+func (s *eventStream) Comment(comment string) error {
+	var buf bytes.Buffer
+	for _, line := range splitLines(comment) {
+		buf.WriteString(":" + line + "\n")
+	}
+	buf.WriteByte('\n')
+
+	return s.write(&buf)
+}
+
+func (s *eventStream) LastEventID() string {
+	return s.lastEventID
+}
+
+func (s *eventStream) Done() <-chan struct{} {
+	return s.done
+}
+
+// write writes and flushes the data, the stream is closed on errors.
+func (s *eventStream) write(data io.WriterTo) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	select {
+	case <-s.done:
+		if s.err != nil {
+			return s.err
+		}
+		return ErrStreamClosed
+	default:
+	}
+
+	_, err := data.WriteTo(s.writer)
+	if err == errEventField {
+		return err
+	}
+	if err == nil {
+		err = s.flush()
+	}
+	if err != nil {
+		s.err = err
+		s.closeLocked()
+	}
+	return err
+}
+
+// close closes the stream, following writes return error.
+// It waits for the write in progress, so the writer isn't used after close returns.
+func (s *eventStream) close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.closeLocked()
+}
+
+// closeLocked closes the stream, s.mu must be held.
+func (s *eventStream) closeLocked() {
+	select {
+	case <-s.done:
+	default:
+		close(s.done)
+	}
+}
+
+// run runs the handler with heartbeats, the stream is closed when the client disconnected
+// or the handler returns. Heartbeats are stopped before run returns, so the response can end.
+// Panic of the handler is returned as error, errors returned by the handler are ignored.
+func (s *eventStream) run(handler EventStreamHandler, heartbeat time.Duration, disconnected <-chan struct{}) error {
+	var ticks <-chan time.Time
+	if heartbeat > 0 {
+		ticker := time.NewTicker(heartbeat)
+		defer ticker.Stop()
+		ticks = ticker.C
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	defer wg.Wait()
+
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case <-s.done:
+				return
+			case <-disconnected:
+				s.close()
+				return
+			case <-ticks:
+				_ = s.Comment("")
+			}
+		}
+	}()
+
+	err := runEventStreamHandler(handler, s)
+	s.close()
+	return err
+}
+
+// runEventStreamHandler runs the handler and returns its panic as error. FiberApp runs the handler
+// in the body stream writer after the request handlers returned, so recover middleware doesn't catch it.
+func runEventStreamHandler(handler EventStreamHandler, stream EventStream) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("sse: handler panic: %v\n%s", r, debug.Stack())
+		}
+	}()
+	_ = handler(stream)
+	return nil
+}
+
+// splitLines splits the text by CRLF, CR and LF line breaks.
+func splitLines(text string) []string {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	text = strings.ReplaceAll(text, "\r", "\n")
+	return strings.Split(text, "\n")
+}
+
+// heartbeatInterval returns the heartbeat interval passed to Context.SSE.
+func heartbeatInterval(heartbeat []time.Duration) time.Duration {
+	if len(heartbeat) > 0 {
+		return heartbeat[0]
+	}
+	return DefaultHeartbeatInterval
+}
+
+func newEventStream(w io.Writer, flush func() error, lastEventID string) *eventStream {
+	return &eventStream{
+		writer:      w,
+		flush:       flush,
+		lastEventID: lastEventID,
+		done:        make(chan struct{}),
+	}
+}
+
+// eventStreams - event streams of the server, which are closed on shutdown
+type eventStreams struct {
+	mu      sync.Mutex
+	streams map[*eventStream]struct{}
+	closing bool
+}
+
+// run runs the stream until it ends, streams started after shutdown are closed immediately.
+// Panic of the handler is returned as error.
+func (e *eventStreams) run(stream *eventStream, handler EventStreamHandler, heartbeat time.Duration,
+	disconnected <-chan struct{}) error {
+	e.mu.Lock()
+	if e.closing {
+		e.mu.Unlock()
+		stream.close()
+		return nil
+	}
+	if e.streams == nil {
+		e.streams = make(map[*eventStream]struct{})
+	}
+	e.streams[stream] = struct{}{}
+	e.mu.Unlock()
+
+	defer func() {
+		e.mu.Lock()
+		delete(e.streams, stream)
+		e.mu.Unlock()
+	}()
+
+	return stream.run(handler, heartbeat, disconnected)
+}
+
+// shutdown closes streams, so their handlers return and responses end.
+// Streams are closed in background, as their writes may be blocked by clients until connections are closed.
+func (e *eventStreams) shutdown() {
+	e.mu.Lock()
+	e.closing = true
+	streams := make([]*eventStream, 0, len(e.streams))
+	for stream := range e.streams {
+		streams = append(streams, stream)
+	}
+	e.mu.Unlock()
+
+	for _, stream := range streams {
+		go stream.close()
+	}
+}
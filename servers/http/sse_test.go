@@ -0,0 +1,142 @@
+package http_test
+
+import (
+	"bufio"
+	"github.com/ok93-01-18/go-ms-lib/servers/http"
+	"github.com/ok93-01-18/go-ms-lib/servers/http/internal/servertest"
+	"io"
+	nethttp "net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func getStream(t *testing.T, addr string, lastEventID string) *nethttp.Response {
+	t.Helper()
+
+	req, err := nethttp.NewRequest(nethttp.MethodGet, "http://"+addr+"/events", nil)
+	if err != nil {
+		t.Fatalf("NewRequest() = %v", err)
+	}
+	if lastEventID != "" {
+		req.Header.Set("Last-Event-ID", lastEventID)
+	}
+
+	client := &nethttp.Client{Timeout: 5 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("GET /events: %v", err)
+	}
+	t.Cleanup(func() { _ = resp.Body.Close() })
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+	if got := resp.Header.Get("Content-Type"); got != "text/event-stream" {
+		t.Errorf("Content-Type = %q, want text/event-stream", got)
+	}
+	return resp
+}
+
+func TestContext_SSE(t *testing.T) {
+	servertest.Run(t, func(t *testing.T, server http.Server) {
+		server.Get("/events", func(ctx http.Context) error {
+			return ctx.SSE(func(stream http.EventStream) error {
+				err := stream.Send(http.Event{ID: stream.LastEventID() + "1", Event: "greeting", Data: "hello\nworld"})
+				if err != nil {
+					return err
+				}
+				return stream.Send(http.Event{Data: "bye", Retry: time.Second})
+			}, 0)
+		})
+
+		resp := getStream(t, servertest.Serve(t, server), "4")
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatalf("read body: %v", err)
+		}
+
+		want := "id: 41\nevent: greeting\ndata: hello\ndata: world\n\nretry: 1000\ndata: bye\n\n"
+		if string(body) != want {
+			t.Errorf("body = %q, want %q", body, want)
+		}
+	})
+}
+
+func TestContext_SSEHeartbeat(t *testing.T) {
+	servertest.Run(t, func(t *testing.T, server http.Server) {
+		server.Get("/events", func(ctx http.Context) error {
+			return ctx.SSE(func(stream http.EventStream) error {
+				time.Sleep(100 * time.Millisecond)
+				return nil
+			}, 10*time.Millisecond)
+		})
+
+		resp := getStream(t, servertest.Serve(t, server), "")
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatalf("read body: %v", err)
+		}
+		if !strings.HasPrefix(string(body), ":\n\n") {
+			t.Errorf("body = %q, want heartbeat comments", body)
+		}
+	})
+}
+
+func TestContext_SSEDisconnect(t *testing.T) {
+	servertest.Run(t, func(t *testing.T, server http.Server) {
+		done := make(chan error, 1)
+		server.Get("/events", func(ctx http.Context) error {
+			return ctx.SSE(func(stream http.EventStream) error {
+				if err := stream.Send(http.Event{Data: "first"}); err != nil {
+					return err
+				}
+				<-stream.Done()
+				done <- stream.Comment("after disconnect")
+				return nil
+			}, 10*time.Millisecond)
+		})
+
+		resp := getStream(t, servertest.Serve(t, server), "")
+		line, err := bufio.NewReader(resp.Body).ReadString('\n')
+		if err != nil || line != "data: first\n" {
+			t.Fatalf("first line = %q, %v", line, err)
+		}
+		_ = resp.Body.Close()
+
+		// the stream is closed by the failed heartbeat at the latest
+		select {
+		case err = <-done:
+			if err == nil {
+				t.Error("write after disconnect returned no error")
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("stream isn't closed after the client disconnected")
+		}
+	})
+}
+
+func TestContext_SSEHandlerPanic(t *testing.T) {
+	servertest.Run(t, func(t *testing.T, server http.Server) {
+		server.Get("/events", func(ctx http.Context) error {
+			return ctx.SSE(func(stream http.EventStream) error {
+				if err := stream.Send(http.Event{Data: "first"}); err != nil {
+					return err
+				}
+				panic("stream panic")
+			}, 0)
+		})
+		addr := servertest.Serve(t, server)
+
+		// the stream ends after the panic and the server keeps serving
+		for i := 0; i < 2; i++ {
+			body, err := io.ReadAll(getStream(t, addr, "").Body)
+			if err != nil {
+				t.Fatalf("read body: %v", err)
+			}
+			if string(body) != "data: first\n\n" {
+				t.Errorf("body = %q, want the event sent before the panic", body)
+			}
+		}
+	})
+}
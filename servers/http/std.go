@@ -0,0 +1,1187 @@
+package http
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"github.com/ok93-01-18/go-ms-lib/servers/http/internal/binding"
+	"github.com/ok93-01-18/go-ms-lib/servers/http/internal/response"
+	"github.com/ok93-01-18/go-ms-lib/views"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net"
+	nethttp "net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	stdMethodUse       = "USE"
+	stdMultipartMemory = 32 << 20
+	stdContentTypeText = "text/plain; charset=utf-8"
+)
+
+// StdServer - wrapper of net/http Server
+type StdServer struct {
+	hooks
+	server       *nethttp.Server
+	stack        []*stdRoute
+	routes       *routeScope
+	errorHandler ErrorHandler
+	views        views.Engine
+	eventStreams eventStreams
+	websockets   websockets
+}
+
+func (s *StdServer) Get(path string, handlers ...Handler) Router {
+	s.handle(s.routes, path, handlers, nethttp.MethodGet)
+	return s
+}
+
+func (s *StdServer) Head(path string, handlers ...Handler) Router {
+	s.handle(s.routes, path, handlers, nethttp.MethodHead)
+	return s
+}
+
+func (s *StdServer) Post(path string, handlers ...Handler) Router {
+	s.handle(s.routes, path, handlers, nethttp.MethodPost)
+	return s
+}
+
+func (s *StdServer) Options(path string, handlers ...Handler) Router {
+	s.handle(s.routes, path, handlers, nethttp.MethodOptions)
+	return s
+}
+
+func (s *StdServer) Delete(path string, handlers ...Handler) Router {
+	s.handle(s.routes, path, handlers, nethttp.MethodDelete)
+	return s
+}
+
+func (s *StdServer) Put(path string, handlers ...Handler) Router {
+	s.handle(s.routes, path, handlers, nethttp.MethodPut)
+	return s
+}
+
+func (s *StdServer) Patch(path string, handlers ...Handler) Router {
+	s.handle(s.routes, path, handlers, nethttp.MethodPatch)
+	return s
+}
+
+func (s *StdServer) Connect(path string, handlers ...Handler) Router {
+	s.handle(s.routes, path, handlers, nethttp.MethodConnect)
+	return s
+}
+
+func (s *StdServer) Trace(path string, handlers ...Handler) Router {
+	s.handle(s.routes, path, handlers, nethttp.MethodTrace)
+	return s
+}
+
+func (s *StdServer) All(path string, handlers ...Handler) Router {
+	s.handle(s.routes, path, handlers, allMethods...)
+	return s
+}
+
+func (s *StdServer) Add(method, path string, handlers ...Handler) Router {
+	s.handle(s.routes, path, handlers, strings.ToUpper(method))
+	return s
+}
+
+func (s *StdServer) Use(args ...interface{}) Router {
+	prefix, handlers := stdUseArgs(args)
+	s.register(stdMethodUse, prefix, handlers...)
+	return s
+}
+
+func (s *StdServer) WebSocket(path string, handler WebSocketHandler) Router {
+	s.handle(s.routes, path, []Handler{s.websocketHandler(handler)}, nethttp.MethodGet)
+	return s
+}
+
+func (s *StdServer) Group(prefix string, handlers ...Handler) Router {
+	if len(handlers) > 0 {
+		s.register(stdMethodUse, prefix, handlers...)
+	}
+	return NewStdGroup(s, prefix)
+}
+
+func (s *StdServer) Name(name string) Router {
+	s.routes.name(name)
+	return s
+}
+
+func (s *StdServer) Meta(key string, value interface{}) Router {
+	s.routes.meta(key, value)
+	return s
+}
+
+func (s *StdServer) Routes() []Route {
+	return s.routes.list()
+}
+
+func (s *StdServer) Listener(ln net.Listener) error {
+	err := s.executeStartup()
+	if err != nil {
+		return err
+	}
+	return stdServeError(s.server.Serve(ln))
+}
+
+func (s *StdServer) ListenTLS(addr, certFile, keyFile string) error {
+	return listenTLS(s, addr, &CertReloaderConfig{CertFile: certFile, KeyFile: keyFile})
+}
+
+func (s *StdServer) ListenMutualTLS(addr, certFile, keyFile, clientCAFile string) error {
+	return listenTLS(s, addr, &CertReloaderConfig{CertFile: certFile, KeyFile: keyFile, ClientCAFile: clientCAFile})
+}
+
+// ListenTLSReloader - HTTP/2 is negotiated with clients supporting it, as net/http ListenAndServeTLS does.
+func (s *StdServer) ListenTLSReloader(addr string, reloader *CertReloader) error {
+	return listenTLSReloader(s, addr, reloader, "h2", "http/1.1")
+}
+
+func (s *StdServer) Listen(addr string) error {
+	err := s.executeStartup()
+	if err != nil {
+		return err
+	}
+	s.server.Addr = addr
+	return stdServeError(s.server.ListenAndServe())
+}
+
+func (s *StdServer) Shutdown() error {
+	return s.ShutdownWithContext(context.Background())
+}
+
+// ShutdownWithContext - active connections are closed forcibly when the context is done.
+func (s *StdServer) ShutdownWithContext(ctx context.Context) error {
+	s.eventStreams.shutdown()
+
+	err := s.server.Shutdown(ctx)
+	if err != nil && ctx.Err() != nil {
+		_ = s.server.Close()
+	}
+
+	wsErr := s.websockets.shutdown(ctx)
+	hooksErr := s.executeShutdown(ctx)
+	if err != nil {
+		return err
+	}
+	if wsErr != nil {
+		return wsErr
+	}
+	return hooksErr
+}
+
+func (s *StdServer) Test(req *nethttp.Request, timeout time.Duration) (*nethttp.Response, error) {
+	recorder := httptest.NewRecorder()
+	if timeout <= 0 {
+		s.ServeHTTP(recorder, req)
+		return recorder.Result(), nil
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		s.ServeHTTP(recorder, req)
+	}()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case <-done:
+		return recorder.Result(), nil
+	case <-timer.C:
+		return nil, fmt.Errorf("test: timeout after %v", timeout)
+	}
+}
+
+func (s *StdServer) ShutdownWithTimeout(timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	return s.ShutdownWithContext(ctx)
+}
+
+// ServeHTTP dispatches the request to the registered routes, so StdServer can be used as net/http Handler.
+func (s *StdServer) ServeHTTP(w nethttp.ResponseWriter, r *nethttp.Request) {
+	ctx := newStdContext(s, w, r)
+
+	err := ctx.Next()
+	if err != nil && s.errorHandler != nil {
+		err = s.errorHandler(ctx, err)
+	}
+	if err != nil {
+		stdDefaultErrorHandler(ctx, err)
+	}
+
+	ctx.flush()
+}
+
+func (s *StdServer) SetErrorHandler(handler ErrorHandler) {
+	s.errorHandler = handler
+}
+
+func (s *StdServer) SetViews(engine views.Engine) {
+	s.views = engine
+}
+
+func (s *StdServer) register(method, path string, handlers ...Handler) {
+	if len(handlers) == 0 {
+		panic(fmt.Sprintf("missing handler in route: %s\n", path))
+	}
+
+	if path == "" || path[0] != '/' {
+		path = "/" + path
+	}
+
+	s.stack = append(s.stack, &stdRoute{
+		method:   method,
+		path:     path,
+		segments: splitPath(path),
+		handlers: handlers,
+	})
+}
+
+// handle registers the route of the path relative to the scope prefix for the methods and adds it to the scope.
+func (s *StdServer) handle(routes *routeScope, path string, handlers []Handler, methods ...string) {
+	for _, method := range methods {
+		s.register(method, joinPath(routes.prefix, path), handlers...)
+	}
+	routes.add(path, methods...)
+}
+
+// websocketHandler - upgrades the request, the handler is run on the hijacked connection before the route returns.
+func (s *StdServer) websocketHandler(handler WebSocketHandler) Handler {
+	return func(ctx Context) error {
+		sc, ok := ctx.(*StdContext)
+		if !ok {
+			return InternalServerError().Wrap(fmt.Errorf("websocket: unexpected context %T", ctx))
+		}
+
+		accept, err := websocketAccept(sc)
+		if err != nil {
+			return err
+		}
+
+		conn, reader, err := sc.upgrade(accept)
+		if err != nil {
+			return InternalServerError().Wrap(err)
+		}
+
+		s.websockets.serve(newWebsocketConn(conn, reader, sc.websocketRequest()), handler)
+		return nil
+	}
+}
+
+// pathExists checks if any route, registered for another method, matches the path.
+func (s *StdServer) pathExists(segments []string) bool {
+	for _, route := range s.stack {
+		if route.method == stdMethodUse {
+			continue
+		}
+		if _, ok := route.match(route.method, segments); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func stdUseArgs(args []interface{}) (string, []Handler) {
+	var prefix string
+	var handlers []Handler
+
+	for _, arg := range args {
+		switch arg := arg.(type) {
+		case string:
+			prefix = arg
+		case Handler:
+			handlers = append(handlers, arg)
+		case func(Context) error:
+			handlers = append(handlers, arg)
+		default:
+			panic(fmt.Sprintf("use: invalid handler %v\n", reflect.TypeOf(arg)))
+		}
+	}
+
+	return prefix, handlers
+}
+
+func stdServeError(err error) error {
+	if err == nethttp.ErrServerClosed {
+		return nil
+	}
+	return err
+}
+
+// stdDefaultErrorHandler - responds with the error text like the default Fiber error handler does
+func stdDefaultErrorHandler(ctx *StdContext, err error) {
+	message := err.Error()
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) {
+		message = httpErr.Message
+	}
+
+	ctx.send(nil)
+	ctx.Set("Content-Type", stdContentTypeText)
+	ctx.Status(ErrorStatusCode(err))
+	_, _ = ctx.WriteString(message)
+}
+
+// NewStdServer - return wrapper of net/http Server
+func NewStdServer(srv *nethttp.Server) Server {
+	if srv == nil {
+		srv = &nethttp.Server{}
+	}
+
+	s := &StdServer{server: srv, routes: newRouteScope(&routeTable{}, "")}
+	srv.Handler = s
+
+	return s
+}
+
+type stdRoute struct {
+	method   string
+	path     string
+	segments []string
+	handlers []Handler
+}
+
+// match checks the route against request method and path and returns route params.
+func (r *stdRoute) match(method string, segments []string) (map[string]string, bool) {
+	switch r.method {
+	case stdMethodUse:
+	case method:
+	case nethttp.MethodGet:
+		if method != nethttp.MethodHead {
+			return nil, false
+		}
+	default:
+		return nil, false
+	}
+
+	params := make(map[string]string)
+	i := 0
+	for _, segment := range r.segments {
+		switch {
+		case segment == "*" || segment == "+":
+			rest := strings.Join(segments[minInt(i, len(segments)):], "/")
+			if segment == "+" && rest == "" {
+				return nil, false
+			}
+			params[segment] = rest
+			return params, true
+		case strings.HasPrefix(segment, ":"):
+			name := segment[1:]
+			optional := strings.HasSuffix(name, "?")
+			if optional {
+				name = name[:len(name)-1]
+			}
+			if i >= len(segments) {
+				if !optional {
+					return nil, false
+				}
+				params[name] = ""
+				continue
+			}
+			value, err := url.PathUnescape(segments[i])
+			if err != nil {
+				value = segments[i]
+			}
+			params[name] = value
+			i++
+		default:
+			if i >= len(segments) || !strings.EqualFold(segment, segments[i]) {
+				return nil, false
+			}
+			i++
+		}
+	}
+
+	// middleware matches any path with the registered prefix
+	if r.method != stdMethodUse && i < len(segments) {
+		return nil, false
+	}
+
+	return params, true
+}
+
+func splitPath(path string) []string {
+	path = strings.Trim(path, "/")
+	if path == "" {
+		return nil
+	}
+	return strings.Split(path, "/")
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// StdContext - wrapper on net/http request and response writer
+type StdContext struct {
+	server       *StdServer
+	writer       nethttp.ResponseWriter
+	request      *nethttp.Request
+	req          *StdRequest
+	method       string
+	segments     []string
+	query        url.Values
+	route        *stdRoute
+	routeIndex   int
+	handlerIndex int
+	params       map[string]string
+	locals       map[string]interface{}
+	status       int
+	body         bytes.Buffer
+	stream       io.Reader
+	streamSize   int
+	written      bool
+}
+
+func (s *StdContext) IP() string {
+	host, _, err := net.SplitHostPort(s.request.RemoteAddr)
+	if err != nil {
+		return s.request.RemoteAddr
+	}
+	return host
+}
+
+func (s *StdContext) Hostname() string {
+	return s.request.Host
+}
+
+func (s *StdContext) Query(key string, defaultValue ...string) string {
+	if s.query == nil {
+		s.query = s.request.URL.Query()
+	}
+	return defaultString(s.query.Get(key), defaultValue)
+}
+
+func (s *StdContext) Set(key string, val string) {
+	s.writer.Header().Set(key, val)
+}
+
+func (s *StdContext) Append(field string, values ...string) {
+	if len(values) == 0 {
+		return
+	}
+
+	h := s.writer.Header().Get(field)
+	originalH := h
+	for _, value := range values {
+		if len(h) == 0 {
+			h = value
+		} else if h != value && !strings.HasPrefix(h, value+",") && !strings.HasSuffix(h, " "+value) &&
+			!strings.Contains(h, " "+value+",") {
+			h += ", " + value
+		}
+	}
+	if originalH != h {
+		s.Set(field, h)
+	}
+}
+
+func (s *StdContext) Write(p []byte) (int, error) {
+	return s.body.Write(p)
+}
+
+func (s *StdContext) Status(status int) Context {
+	s.status = status
+	return s
+}
+
+func (s *StdContext) GetReqHeaders() map[string]string {
+	headers := make(map[string]string, len(s.request.Header))
+	for key, values := range s.request.Header {
+		headers[key] = strings.Join(values, ", ")
+	}
+	return headers
+}
+
+func (s *StdContext) Request() Request {
+	return s.req
+}
+
+func (s *StdContext) Response() Response {
+	return &StdResponse{context: s}
+}
+
+func (s *StdContext) Context() context.Context {
+	return s.request.Context()
+}
+
+func (s *StdContext) Route() Route {
+	if s.route == nil {
+		return Route{Method: s.method, Path: "/"}
+	}
+	return Route{Method: s.method, Path: s.route.path}
+}
+
+func (s *StdContext) Writef(f string, a ...interface{}) (int, error) {
+	return fmt.Fprintf(&s.body, f, a...)
+}
+
+func (s *StdContext) WriteString(str string) (int, error) {
+	return s.body.WriteString(str)
+}
+
+func (s *StdContext) BodyParser(out interface{}) error {
+	contentType, _, _ := mime.ParseMediaType(s.request.Header.Get("Content-Type"))
+
+	switch {
+	case strings.HasSuffix(contentType, "json"):
+		return json.Unmarshal(s.req.Body(), out)
+	case strings.HasSuffix(contentType, "xml"):
+		return xml.Unmarshal(s.req.Body(), out)
+	case contentType == "application/x-www-form-urlencoded":
+		s.req.Body()
+		err := s.request.ParseForm()
+		if err != nil {
+			return err
+		}
+		return binding.Decode(out, s.request.PostForm, "form", false)
+	case contentType == "multipart/form-data":
+		s.req.Body()
+		err := s.request.ParseMultipartForm(stdMultipartMemory)
+		if err != nil {
+			return err
+		}
+		return binding.Decode(out, s.request.MultipartForm.Value, "form", false)
+	}
+
+	return UnprocessableEntity()
+}
+
+func (s *StdContext) BindQuery(out interface{}) error {
+	return bindQuery(s, out)
+}
+
+func (s *StdContext) BindParams(out interface{}) error {
+	return bindParams(s.params, out)
+}
+
+func (s *StdContext) BindHeaders(out interface{}) error {
+	return bindHeaders(s, out)
+}
+
+func (s *StdContext) Next() error {
+	s.handlerIndex++
+	if s.route != nil && s.handlerIndex < len(s.route.handlers) {
+		return s.route.handlers[s.handlerIndex](s)
+	}
+
+	for s.routeIndex++; s.routeIndex < len(s.server.stack); s.routeIndex++ {
+		route := s.server.stack[s.routeIndex]
+		params, ok := route.match(s.method, s.segments)
+		if !ok {
+			continue
+		}
+
+		s.route = route
+		s.params = params
+		s.handlerIndex = 0
+		return route.handlers[0](s)
+	}
+
+	if s.server.pathExists(s.segments) {
+		return MethodNotAllowed()
+	}
+	return NotFound("Cannot " + s.method + " " + s.request.URL.Path)
+}
+
+func (s *StdContext) Redirect(location string, status int) error {
+	s.Set("Location", location)
+	s.Status(status)
+	return nil
+}
+
+func (s *StdContext) Locals(key string, value ...interface{}) (val interface{}) {
+	if len(value) == 0 {
+		return s.locals[key]
+	}
+
+	s.locals[key] = value[0]
+	return value[0]
+}
+
+func (s *StdContext) Get(key string, defaultValue ...string) string {
+	return defaultString(s.request.Header.Get(key), defaultValue)
+}
+
+func (s *StdContext) Method(override ...string) string {
+	if len(override) > 0 {
+		s.method = strings.ToUpper(override[0])
+	}
+	return s.method
+}
+
+func (s *StdContext) Params(key string, defaultValue ...string) string {
+	return defaultString(s.params[key], defaultValue)
+}
+
+func (s *StdContext) JSON(data interface{}) error {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	s.Set("Content-Type", "application/json")
+	s.send(raw)
+	return nil
+}
+
+func (s *StdContext) XML(data interface{}) error {
+	raw, err := xml.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	s.Set("Content-Type", "application/xml")
+	s.send(raw)
+	return nil
+}
+
+// SendFile - compression is not supported, so the compress argument is ignored.
+func (s *StdContext) SendFile(file string, _ ...bool) error {
+	f, err := os.Open(filepath.Clean(file))
+	if err != nil {
+		return NotFound(fmt.Sprintf("sendfile: file %s not found", file))
+	}
+
+	stat, err := f.Stat()
+	if err != nil || stat.IsDir() {
+		_ = f.Close()
+		return NotFound(fmt.Sprintf("sendfile: file %s not found", file))
+	}
+
+	contentType := mime.TypeByExtension(filepath.Ext(file))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	s.Set("Content-Type", contentType)
+	s.Set("Last-Modified", stat.ModTime().UTC().Format(nethttp.TimeFormat))
+	return s.SendStream(f, int(stat.Size()))
+}
+
+func (s *StdContext) SendStream(stream io.Reader, size ...int) error {
+	s.body.Reset()
+	s.stream = stream
+	s.streamSize = -1
+	if len(size) > 0 && size[0] >= 0 {
+		s.streamSize = size[0]
+	}
+	return nil
+}
+
+func (s *StdContext) Cookie(cookie *Cookie) {
+	s.deleteSetCookie(cookie.Name)
+	nethttp.SetCookie(s.writer, response.StdCookie(cookie))
+}
+
+func (s *StdContext) ClearCookie(key ...string) {
+	if len(key) == 0 {
+		for _, cookie := range s.request.Cookies() {
+			key = append(key, cookie.Name)
+		}
+	}
+
+	for _, name := range key {
+		s.deleteSetCookie(name)
+		nethttp.SetCookie(s.writer, response.ExpiredCookie(name))
+	}
+}
+
+func (s *StdContext) Type(extension string, charset ...string) Context {
+	contentType := stdMimeType(extension)
+	if len(charset) > 0 {
+		contentType += "; charset=" + charset[0]
+	}
+
+	s.Set("Content-Type", contentType)
+	return s
+}
+
+func (s *StdContext) SendStatus(status int) error {
+	s.Status(status)
+
+	if s.body.Len() == 0 && s.stream == nil {
+		_, _ = s.WriteString(nethttp.StatusText(status))
+	}
+	return nil
+}
+
+func (s *StdContext) Render(name string, params map[string]string, layouts ...string) error {
+	return render(s, s.server.views, name, params, layouts)
+}
+
+func (s *StdContext) Accepts(offers ...string) string {
+	return binding.Accepted(s.request.Header.Get("Accept"), offers...)
+}
+
+func (s *StdContext) ClientCertificate() *x509.Certificate {
+	return VerifiedClientCertificate(s.request.TLS)
+}
+
+// SSE - events are written directly to the response writer, the handler is run before SSE returns.
+func (s *StdContext) SSE(handler EventStreamHandler, heartbeat ...time.Duration) error {
+	flusher, ok := s.writer.(nethttp.Flusher)
+	if !ok {
+		return InternalServerError().Wrap(errors.New("sse: response writer can't be flushed"))
+	}
+
+	response.SetEventStreamHeaders(s.Set)
+	s.Status(StatusOK)
+	s.written = true
+	s.writer.WriteHeader(StatusOK)
+	flusher.Flush()
+
+	flush := func() error {
+		flusher.Flush()
+		return nil
+	}
+	stream := newEventStream(s.writer, flush, s.request.Header.Get("Last-Event-ID"))
+	// panic is passed to the error handler to be logged, the response is already sent
+	err := s.server.eventStreams.run(stream, handler, heartbeatInterval(heartbeat), s.request.Context().Done())
+	if err != nil {
+		return InternalServerError().Wrap(err)
+	}
+	return nil
+}
+
+// send replaces the response body.
+func (s *StdContext) send(body []byte) {
+	s.stream = nil
+	s.body.Reset()
+	s.body.Write(body)
+}
+
+// deleteSetCookie removes the previously set cookie with the given name from the response.
+func (s *StdContext) deleteSetCookie(name string) {
+	header := s.writer.Header()
+	cookies := header.Values("Set-Cookie")
+	header.Del("Set-Cookie")
+	for _, cookie := range cookies {
+		if !strings.HasPrefix(cookie, name+"=") {
+			header.Add("Set-Cookie", cookie)
+		}
+	}
+}
+
+// upgrade hijacks the connection and writes the response switching to the websocket protocol.
+func (s *StdContext) upgrade(accept string) (net.Conn, *bufio.Reader, error) {
+	hijacker, ok := s.writer.(nethttp.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("websocket: response writer can't be hijacked")
+	}
+
+	conn, rw, err := hijacker.Hijack()
+	if err != nil {
+		return nil, nil, err
+	}
+	s.written = true
+	s.Status(StatusSwitchingProtocols)
+
+	s.Set("Upgrade", "websocket")
+	s.Set("Connection", "Upgrade")
+	s.Set("Sec-WebSocket-Accept", accept)
+
+	var response bytes.Buffer
+	response.WriteString("HTTP/1.1 101 Switching Protocols\r\n")
+	_ = s.writer.Header().Write(&response)
+	response.WriteString("\r\n")
+
+	_, err = conn.Write(response.Bytes())
+	if err != nil {
+		_ = conn.Close()
+		return nil, nil, err
+	}
+	return conn, rw.Reader, nil
+}
+
+// websocketRequest returns copy of the request data available on the websocket connection.
+func (s *StdContext) websocketRequest() *websocketRequest {
+	req := &websocketRequest{
+		params: make(map[string]string, len(s.params)),
+		locals: make(map[string]interface{}, len(s.locals)),
+		query:  s.request.URL.Query(),
+		ip:     s.IP(),
+	}
+	for key, value := range s.params {
+		req.params[key] = value
+	}
+	for key, value := range s.locals {
+		req.locals[key] = value
+	}
+	return req
+}
+
+// flush writes buffered status, headers and body to the response writer.
+// Nothing is written if the response was written directly, e.g. by websocket upgrade or SSE.
+func (s *StdContext) flush() {
+	if s.written {
+		return
+	}
+
+	if closer, ok := s.stream.(io.Closer); ok {
+		defer closer.Close()
+	}
+
+	// responses with these statuses must not have a body
+	if s.status < StatusOK || s.status == StatusNoContent || s.status == StatusNotModified {
+		s.writer.WriteHeader(s.status)
+		return
+	}
+
+	header := s.writer.Header()
+	if header.Get("Content-Type") == "" && (s.body.Len() > 0 || s.stream != nil) {
+		header.Set("Content-Type", stdContentTypeText)
+	}
+
+	if s.stream == nil {
+		header.Set("Content-Length", strconv.Itoa(s.body.Len()))
+	} else if s.streamSize >= 0 {
+		header.Set("Content-Length", strconv.Itoa(s.streamSize))
+	}
+
+	s.writer.WriteHeader(s.status)
+	if s.request.Method == nethttp.MethodHead {
+		return
+	}
+
+	if s.stream != nil {
+		_, _ = io.Copy(s.writer, s.stream)
+		return
+	}
+	_, _ = s.writer.Write(s.body.Bytes())
+}
+
+// stdMimeType returns MIME type of the file extension without parameters.
+func stdMimeType(extension string) string {
+	if extension == "" {
+		return ""
+	}
+	if extension[0] != '.' {
+		extension = "." + extension
+	}
+
+	contentType := mime.TypeByExtension(extension)
+	if contentType == "" {
+		return "application/octet-stream"
+	}
+
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return contentType
+	}
+	return mediaType
+}
+
+func defaultString(value string, defaultValue []string) string {
+	if value == "" && len(defaultValue) > 0 {
+		return defaultValue[0]
+	}
+	return value
+}
+
+func newStdContext(s *StdServer, w nethttp.ResponseWriter, r *nethttp.Request) *StdContext {
+	return &StdContext{
+		server:       s,
+		writer:       w,
+		request:      r,
+		req:          newStdRequest(r),
+		method:       r.Method,
+		segments:     splitPath(r.URL.Path),
+		routeIndex:   -1,
+		handlerIndex: -1,
+		locals:       make(map[string]interface{}),
+		status:       StatusOK,
+	}
+}
+
+// StdRequest - wrapper on net/http request
+type StdRequest struct {
+	request  *nethttp.Request
+	body     []byte
+	bodyRead bool
+}
+
+func (s *StdRequest) GetContentLength() int {
+	return int(s.request.ContentLength)
+}
+
+// Body reads the whole request body once and keeps it for the next calls.
+func (s *StdRequest) Body() []byte {
+	if s.bodyRead || s.request.Body == nil {
+		return s.body
+	}
+
+	s.bodyRead = true
+	body, err := io.ReadAll(s.request.Body)
+	if err != nil {
+		return s.body
+	}
+	_ = s.request.Body.Close()
+
+	s.body = body
+	s.request.Body = io.NopCloser(bytes.NewReader(body))
+	return s.body
+}
+
+func (s *StdRequest) RequestURI() string {
+	if s.request.RequestURI != "" {
+		return s.request.RequestURI
+	}
+	return s.request.URL.RequestURI()
+}
+
+func (s *StdRequest) Cookies(key string, defaultValue ...string) string {
+	cookie, err := s.request.Cookie(key)
+	if err != nil {
+		return defaultString("", defaultValue)
+	}
+	return defaultString(cookie.Value, defaultValue)
+}
+
+func (s *StdRequest) FormValue(key string, defaultValue ...string) string {
+	s.Body()
+	return defaultString(s.request.FormValue(key), defaultValue)
+}
+
+func (s *StdRequest) MultipartForm() (*multipart.Form, error) {
+	s.Body()
+	err := s.request.ParseMultipartForm(stdMultipartMemory)
+	if err != nil {
+		return nil, err
+	}
+	return s.request.MultipartForm, nil
+}
+
+func (s *StdRequest) FormFile(key string) (*multipart.FileHeader, error) {
+	form, err := s.MultipartForm()
+	if err != nil {
+		return nil, err
+	}
+
+	files := form.File[key]
+	if len(files) == 0 {
+		return nil, nethttp.ErrMissingFile
+	}
+	return files[0], nil
+}
+
+func (s *StdRequest) Path() string {
+	return s.request.URL.Path
+}
+
+func (s *StdRequest) QueryString() string {
+	return s.request.URL.RawQuery
+}
+
+func (s *StdRequest) Scheme() string {
+	if s.request.TLS != nil {
+		return "https"
+	}
+	return "http"
+}
+
+func (s *StdRequest) Protocol() string {
+	return s.request.Proto
+}
+
+// VisitHeaders - net/http keeps Host apart from other headers, so it is visited first.
+func (s *StdRequest) VisitHeaders(visitor func(key, value string)) {
+	if s.request.Host != "" {
+		visitor("Host", s.request.Host)
+	}
+	visitStdHeaders(s.request.Header, visitor)
+}
+
+func newStdRequest(r *nethttp.Request) *StdRequest {
+	return &StdRequest{request: r}
+}
+
+// StdResponse - buffered response of StdContext
+type StdResponse struct {
+	context *StdContext
+}
+
+func (s *StdResponse) StatusCode() int {
+	return s.context.status
+}
+
+func (s *StdResponse) Body() []byte {
+	ctx := s.context
+	if ctx.stream != nil {
+		stream := ctx.stream
+		_, _ = ctx.body.ReadFrom(stream)
+		if closer, ok := stream.(io.Closer); ok {
+			_ = closer.Close()
+		}
+		ctx.stream = nil
+	}
+	return ctx.body.Bytes()
+}
+
+func (s *StdResponse) BodySize() int {
+	if s.context.stream != nil {
+		return s.context.streamSize
+	}
+	return s.context.body.Len()
+}
+
+func (s *StdResponse) Header(key string) string {
+	return s.context.writer.Header().Get(key)
+}
+
+func (s *StdResponse) VisitHeaders(visitor func(key, value string)) {
+	visitStdHeaders(s.context.writer.Header(), visitor)
+}
+
+func (s *StdResponse) Reset() {
+	ctx := s.context
+	if ctx.written {
+		return
+	}
+
+	header := ctx.writer.Header()
+	for key := range header {
+		delete(header, key)
+	}
+	if closer, ok := ctx.stream.(io.Closer); ok {
+		_ = closer.Close()
+	}
+	ctx.status = StatusOK
+	ctx.send(nil)
+}
+
+// visitStdHeaders visits header values sorted by key.
+func visitStdHeaders(header nethttp.Header, visitor func(key, value string)) {
+	keys := make([]string, 0, len(header))
+	for key := range header {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		for _, value := range header[key] {
+			visitor(key, value)
+		}
+	}
+}
+
+type StdGroup struct {
+	server *StdServer
+	prefix string
+	routes *routeScope
+}
+
+func (sg *StdGroup) Get(path string, handlers ...Handler) Router {
+	sg.server.handle(sg.routes, path, handlers, nethttp.MethodGet)
+	return sg
+}
+
+func (sg *StdGroup) Head(path string, handlers ...Handler) Router {
+	sg.server.handle(sg.routes, path, handlers, nethttp.MethodHead)
+	return sg
+}
+
+func (sg *StdGroup) Post(path string, handlers ...Handler) Router {
+	sg.server.handle(sg.routes, path, handlers, nethttp.MethodPost)
+	return sg
+}
+
+func (sg *StdGroup) Options(path string, handlers ...Handler) Router {
+	sg.server.handle(sg.routes, path, handlers, nethttp.MethodOptions)
+	return sg
+}
+
+func (sg *StdGroup) Delete(path string, handlers ...Handler) Router {
+	sg.server.handle(sg.routes, path, handlers, nethttp.MethodDelete)
+	return sg
+}
+
+func (sg *StdGroup) Put(path string, handlers ...Handler) Router {
+	sg.server.handle(sg.routes, path, handlers, nethttp.MethodPut)
+	return sg
+}
+
+func (sg *StdGroup) Patch(path string, handlers ...Handler) Router {
+	sg.server.handle(sg.routes, path, handlers, nethttp.MethodPatch)
+	return sg
+}
+
+func (sg *StdGroup) Connect(path string, handlers ...Handler) Router {
+	sg.server.handle(sg.routes, path, handlers, nethttp.MethodConnect)
+	return sg
+}
+
+func (sg *StdGroup) Trace(path string, handlers ...Handler) Router {
+	sg.server.handle(sg.routes, path, handlers, nethttp.MethodTrace)
+	return sg
+}
+
+func (sg *StdGroup) All(path string, handlers ...Handler) Router {
+	sg.server.handle(sg.routes, path, handlers, allMethods...)
+	return sg
+}
+
+func (sg *StdGroup) Add(method, path string, handlers ...Handler) Router {
+	sg.server.handle(sg.routes, path, handlers, strings.ToUpper(method))
+	return sg
+}
+
+func (sg *StdGroup) Use(args ...interface{}) Router {
+	prefix, handlers := stdUseArgs(args)
+	sg.server.register(stdMethodUse, joinPath(sg.prefix, prefix), handlers...)
+	return sg
+}
+
+func (sg *StdGroup) WebSocket(path string, handler WebSocketHandler) Router {
+	sg.server.handle(sg.routes, path, []Handler{sg.server.websocketHandler(handler)}, nethttp.MethodGet)
+	return sg
+}
+
+func (sg *StdGroup) Group(prefix string, handlers ...Handler) Router {
+	prefix = joinPath(sg.prefix, prefix)
+	if len(handlers) > 0 {
+		sg.server.register(stdMethodUse, prefix, handlers...)
+	}
+	return NewStdGroup(sg.server, prefix)
+}
+
+func (sg *StdGroup) Name(name string) Router {
+	sg.routes.name(name)
+	return sg
+}
+
+func (sg *StdGroup) Meta(key string, value interface{}) Router {
+	sg.routes.meta(key, value)
+	return sg
+}
+
+func (sg *StdGroup) Routes() []Route {
+	return sg.routes.list()
+}
+
+func NewStdGroup(s *StdServer, prefix string) *StdGroup {
+	return &StdGroup{server: s, prefix: prefix, routes: s.routes.group(prefix)}
+}
@@ -0,0 +1,252 @@
+package http
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"github.com/ok93-01-18/go-ms-lib/log"
+	"net"
+	"os"
+	"sync"
+	"time"
+)
+
+// DefaultCertReloadInterval - interval of checking certificate files for changes
+const DefaultCertReloadInterval = 10 * time.Second
+
+type CertReloaderConfig struct {
+	// CertFile - PEM encoded certificate, intermediate certificates may follow the leaf one
+	CertFile string
+
+	// KeyFile - PEM encoded private key of the certificate
+	KeyFile string
+
+	// ClientCAFile - PEM encoded certificates of CAs verifying client certificates.
+	// Client certificates are not requested when empty.
+	ClientCAFile string
+
+	// Interval of checking files for changes, DefaultCertReloadInterval is used when zero.
+	Interval time.Duration
+
+	// Logger receives reload errors to the app channel, they are not logged when nil.
+	Logger log.Logger
+}
+
+// fileState - modification time and size of the file, which are compared to detect changes
+type fileState struct {
+	modTime time.Time
+	size    int64
+}
+
+// CertReloader - loader of the server certificate and client CAs, which reloads them when files change,
+// so certificates can be rotated without restarting the server.
+// Failed reloads keep the previously loaded certificate.
+type CertReloader struct {
+	conf      *CertReloaderConfig
+	mu        sync.RWMutex
+	cert      *tls.Certificate
+	clientCAs *x509.CertPool
+	states    map[string]fileState
+	stop      chan struct{}
+	stopOnce  sync.Once
+}
+
+// Reload loads the files and replaces the certificate and client CAs if all of them are valid.
+func (r *CertReloader) Reload() error {
+	states := make(map[string]fileState, 3)
+	for _, file := range r.files() {
+		state, err := statFile(file)
+		if err != nil {
+			return err
+		}
+		states[file] = state
+	}
+
+	cert, err := tls.LoadX509KeyPair(r.conf.CertFile, r.conf.KeyFile)
+	if err != nil {
+		return err
+	}
+
+	var clientCAs *x509.CertPool
+	if r.conf.ClientCAFile != "" {
+		pem, err := os.ReadFile(r.conf.ClientCAFile)
+		if err != nil {
+			return err
+		}
+
+		clientCAs = x509.NewCertPool()
+		if !clientCAs.AppendCertsFromPEM(pem) {
+			return fmt.Errorf("tls: no certificates found in %s", r.conf.ClientCAFile)
+		}
+	}
+
+	r.mu.Lock()
+	r.cert = &cert
+	r.clientCAs = clientCAs
+	r.states = states
+	r.mu.Unlock()
+
+	return nil
+}
+
+// GetCertificate returns the current certificate, it can be used as tls.Config GetCertificate.
+func (r *CertReloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.cert, nil
+}
+
+// TLSConfig returns the server config using the current certificate.
+// Client certificates are required and verified by the current client CAs if ClientCAFile is set.
+func (r *CertReloader) TLSConfig() *tls.Config {
+	return r.tlsConfig(nil)
+}
+
+// tlsConfig returns the server config of TLSConfig, which negotiates the application protocols.
+func (r *CertReloader) tlsConfig(nextProtos []string) *tls.Config {
+	conf := r.config(nextProtos)
+	if r.conf.ClientCAFile != "" {
+		conf.GetConfigForClient = func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			return r.config(nextProtos), nil
+		}
+	}
+	return conf
+}
+
+// Close stops checking files for changes.
+func (r *CertReloader) Close() {
+	r.stopOnce.Do(func() {
+		close(r.stop)
+	})
+}
+
+// config returns the server config with the current client CAs.
+func (r *CertReloader) config(nextProtos []string) *tls.Config {
+	conf := &tls.Config{
+		MinVersion:     tls.VersionTLS12,
+		GetCertificate: r.GetCertificate,
+		NextProtos:     nextProtos,
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if r.clientCAs != nil {
+		conf.ClientAuth = tls.RequireAndVerifyClientCert
+		conf.ClientCAs = r.clientCAs
+	}
+	return conf
+}
+
+// watch reloads files when they change until the reloader is closed.
+func (r *CertReloader) watch(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.stop:
+			return
+		case <-ticker.C:
+		}
+
+		if !r.changed() {
+			continue
+		}
+
+		err := r.Reload()
+		if err != nil && r.conf.Logger != nil {
+			r.conf.Logger.Errorf(log.TypeApp, "tls: reload of %s failed: %v", r.conf.CertFile, err)
+		}
+	}
+}
+
+// changed checks if any file changed since the last successful reload.
+func (r *CertReloader) changed() bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, file := range r.files() {
+		state, err := statFile(file)
+		if err != nil || state != r.states[file] {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *CertReloader) files() []string {
+	files := []string{r.conf.CertFile, r.conf.KeyFile}
+	if r.conf.ClientCAFile != "" {
+		files = append(files, r.conf.ClientCAFile)
+	}
+	return files
+}
+
+func statFile(file string) (fileState, error) {
+	info, err := os.Stat(file)
+	if err != nil {
+		return fileState{}, err
+	}
+	return fileState{modTime: info.ModTime(), size: info.Size()}, nil
+}
+
+// NewCertReloader - return CertReloader with loaded files, which checks them for changes until it is closed
+func NewCertReloader(conf *CertReloaderConfig) (*CertReloader, error) {
+	if conf.CertFile == "" || conf.KeyFile == "" {
+		return nil, errors.New("tls: certificate and key files are required")
+	}
+
+	r := &CertReloader{conf: conf, stop: make(chan struct{})}
+	err := r.Reload()
+	if err != nil {
+		return nil, err
+	}
+
+	interval := conf.Interval
+	if interval <= 0 {
+		interval = DefaultCertReloadInterval
+	}
+	go r.watch(interval)
+
+	return r, nil
+}
+
+// listenTLS serves TLS connections from the addr by the server until it is shut down,
+// the reloader of the files is closed afterwards.
+func listenTLS(s Server, addr string, conf *CertReloaderConfig) error {
+	reloader, err := NewCertReloader(conf)
+	if err != nil {
+		return err
+	}
+	defer reloader.Close()
+
+	return s.ListenTLSReloader(addr, reloader)
+}
+
+// listenTLSReloader serves TLS connections from the addr with certificates of the reloader,
+// nextProtos are application protocols supported by the server, e.g. "h2".
+func listenTLSReloader(s Server, addr string, reloader *CertReloader, nextProtos ...string) error {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+
+	err = s.Listener(tls.NewListener(ln, reloader.tlsConfig(nextProtos)))
+	if err != nil {
+		// servers which failed before serving, e.g. by startup hook, leave the listener open, so it is closed here
+		_ = ln.Close()
+	}
+	return err
+}
+
+// VerifiedClientCertificate returns the leaf of the first verified client certificate chain of the connection,
+// nil is returned for connections without verified client certificates.
+func VerifiedClientCertificate(state *tls.ConnectionState) *x509.Certificate {
+	if state == nil || len(state.VerifiedChains) == 0 || len(state.VerifiedChains[0]) == 0 {
+		return nil
+	}
+	return state.VerifiedChains[0][0]
+}
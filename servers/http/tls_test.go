@@ -0,0 +1,319 @@
+package http_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"github.com/ok93-01-18/go-ms-lib/log"
+	"github.com/ok93-01-18/go-ms-lib/servers/http"
+	"github.com/ok93-01-18/go-ms-lib/servers/http/internal/servertest"
+	"io"
+	"math/big"
+	"net"
+	nethttp "net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+// testCert - certificate signed by the parent or self-signed without it
+type testCert struct {
+	cert    *x509.Certificate
+	key     *ecdsa.PrivateKey
+	certPEM []byte
+	keyPEM  []byte
+}
+
+func newTestCert(t *testing.T, name string, parent *testCert) *testCert {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey() = %v", err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	signer, signerKey := template, key
+	if parent == nil {
+		template.IsCA = true
+		template.KeyUsage |= x509.KeyUsageCertSign
+		template.BasicConstraintsValid = true
+	} else {
+		signer, signerKey = parent.cert, parent.key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, signer, &key.PublicKey, signerKey)
+	if err != nil {
+		t.Fatalf("CreateCertificate() = %v", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("ParseCertificate() = %v", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("MarshalECPrivateKey() = %v", err)
+	}
+
+	return &testCert{
+		cert:    cert,
+		key:     key,
+		certPEM: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		keyPEM:  pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}),
+	}
+}
+
+// writeFiles writes the certificate and key to the files of the directory.
+func (c *testCert) writeFiles(t *testing.T, dir, name string) (string, string) {
+	t.Helper()
+
+	certFile, keyFile := filepath.Join(dir, name+".crt"), filepath.Join(dir, name+".key")
+	if err := os.WriteFile(certFile, c.certPEM, 0o600); err != nil {
+		t.Fatalf("write certificate: %v", err)
+	}
+	if err := os.WriteFile(keyFile, c.keyPEM, 0o600); err != nil {
+		t.Fatalf("write key: %v", err)
+	}
+	return certFile, keyFile
+}
+
+// freeAddr returns the local address, which is free at the moment.
+func freeAddr(t *testing.T) string {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen() = %v", err)
+	}
+	addr := ln.Addr().String()
+	_ = ln.Close()
+	return addr
+}
+
+// tlsGet makes the request by the transport, retrying connection errors until the server listens.
+func tlsGet(t *testing.T, addr string, transport *nethttp.Transport) (*nethttp.Response, error) {
+	t.Helper()
+
+	client := &nethttp.Client{
+		Timeout:   5 * time.Second,
+		Transport: transport,
+	}
+	defer client.CloseIdleConnections()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		resp, err := client.Get("https://" + addr + "/")
+		var opErr *net.OpError
+		if err == nil || !errors.As(err, &opErr) || opErr.Op != "dial" || time.Now().After(deadline) {
+			return resp, err
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestServer_ListenMutualTLS(t *testing.T) {
+	ca := newTestCert(t, "ca", nil)
+	serverCert := newTestCert(t, "server", ca)
+	clientCert := newTestCert(t, "client", ca)
+
+	dir := t.TempDir()
+	certFile, keyFile := serverCert.writeFiles(t, dir, "server")
+	caFile, _ := ca.writeFiles(t, dir, "ca")
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.cert)
+	clientKeyPair, err := tls.X509KeyPair(clientCert.certPEM, clientCert.keyPEM)
+	if err != nil {
+		t.Fatalf("X509KeyPair() = %v", err)
+	}
+
+	servertest.Run(t, func(t *testing.T, server http.Server) {
+		server.Get("/", func(ctx http.Context) error {
+			_, err := ctx.WriteString(ctx.ClientCertificate().Subject.CommonName)
+			return err
+		})
+
+		addr := freeAddr(t)
+		served := make(chan error, 1)
+		go func() {
+			served <- server.ListenMutualTLS(addr, certFile, keyFile, caFile)
+		}()
+
+		resp, err := tlsGet(t, addr, &nethttp.Transport{TLSClientConfig: &tls.Config{RootCAs: roots, Certificates: []tls.Certificate{clientKeyPair}}})
+		if err != nil {
+			t.Fatalf("GET with client certificate: %v", err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		if string(body) != "client" {
+			t.Errorf("body = %q, want client", body)
+		}
+
+		if _, err = tlsGet(t, addr, &nethttp.Transport{TLSClientConfig: &tls.Config{RootCAs: roots}}); err == nil {
+			t.Error("GET without client certificate succeeded")
+		}
+
+		if err = server.Shutdown(); err != nil {
+			t.Errorf("Shutdown() = %v", err)
+		}
+		if err = <-served; err != nil {
+			t.Errorf("ListenMutualTLS() = %v", err)
+		}
+	})
+}
+
+func TestServer_ListenTLSStartupError(t *testing.T) {
+	cert := newTestCert(t, "server", nil)
+	certFile, keyFile := cert.writeFiles(t, t.TempDir(), "server")
+
+	servertest.Run(t, func(t *testing.T, server http.Server) {
+		server.OnStartup(func() error {
+			return errors.New("startup failed")
+		})
+
+		addr := freeAddr(t)
+		if err := server.ListenTLS(addr, certFile, keyFile); err == nil {
+			t.Fatal("ListenTLS() returned no error")
+		}
+
+		// the listener is closed, so the address can be listened again
+		ln, err := net.Listen("tcp", addr)
+		if err != nil {
+			t.Fatalf("Listen() after failed ListenTLS = %v", err)
+		}
+		_ = ln.Close()
+	})
+}
+
+// errorLogger - log.Logger recording errors
+type errorLogger struct {
+	log.Logger
+	mu     sync.Mutex
+	errors []string
+}
+
+func (l *errorLogger) Errorf(_ log.TypeEnum, format string, args ...interface{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.errors = append(l.errors, fmt.Sprintf(format, args...))
+}
+
+func (l *errorLogger) count() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return len(l.errors)
+}
+
+func TestCertReloader(t *testing.T) {
+	dir := t.TempDir()
+	first := newTestCert(t, "first", nil)
+	certFile, keyFile := first.writeFiles(t, dir, "server")
+
+	logger := &errorLogger{}
+	reloader, err := http.NewCertReloader(&http.CertReloaderConfig{
+		CertFile: certFile,
+		KeyFile:  keyFile,
+		Interval: 10 * time.Millisecond,
+		Logger:   logger,
+	})
+	if err != nil {
+		t.Fatalf("NewCertReloader() = %v", err)
+	}
+	defer reloader.Close()
+
+	commonName := func() string {
+		cert, _ := reloader.GetCertificate(nil)
+		leaf, err := x509.ParseCertificate(cert.Certificate[0])
+		if err != nil {
+			t.Fatalf("ParseCertificate() = %v", err)
+		}
+		return leaf.Subject.CommonName
+	}
+	eventually := func(condition func() bool, message string) {
+		deadline := time.Now().Add(5 * time.Second)
+		for !condition() {
+			if time.Now().After(deadline) {
+				t.Fatal(message)
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+
+	if name := commonName(); name != "first" {
+		t.Errorf("certificate = %s, want first", name)
+	}
+
+	// the key doesn't match the certificate until both files are written
+	second := newTestCert(t, "second", nil)
+	if err = os.WriteFile(certFile, second.certPEM, 0o600); err != nil {
+		t.Fatalf("write certificate: %v", err)
+	}
+	eventually(func() bool { return logger.count() > 0 }, "failed reload isn't logged")
+	if name := commonName(); name != "first" {
+		t.Errorf("certificate after failed reload = %s, want first", name)
+	}
+
+	if err = os.WriteFile(keyFile, second.keyPEM, 0o600); err != nil {
+		t.Fatalf("write key: %v", err)
+	}
+	eventually(func() bool { return commonName() == "second" }, "certificate isn't reloaded")
+}
+
+func TestServer_ListenTLSProtocol(t *testing.T) {
+	cert := newTestCert(t, "server", nil)
+	certFile, keyFile := cert.writeFiles(t, t.TempDir(), "server")
+	roots := x509.NewCertPool()
+	roots.AddCert(cert.cert)
+
+	servertest.Run(t, func(t *testing.T, server http.Server) {
+		server.Get("/", func(ctx http.Context) error {
+			_, err := ctx.WriteString(ctx.Request().Protocol())
+			return err
+		})
+
+		addr := freeAddr(t)
+		served := make(chan error, 1)
+		go func() {
+			served <- server.ListenTLS(addr, certFile, keyFile)
+		}()
+
+		resp, err := tlsGet(t, addr, &nethttp.Transport{TLSClientConfig: &tls.Config{RootCAs: roots}, ForceAttemptHTTP2: true})
+		if err != nil {
+			t.Fatalf("GET: %v", err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+
+		// fasthttp serves HTTP/1.1 only, so HTTP/2 isn't offered by fiber
+		want := "HTTP/2.0"
+		if _, ok := server.(*http.FiberApp); ok {
+			want = "HTTP/1.1"
+		}
+		if resp.Proto != want || string(body) != want {
+			t.Errorf("protocol = %s, handled as %s, want %s", resp.Proto, body, want)
+		}
+
+		if err = server.Shutdown(); err != nil {
+			t.Errorf("Shutdown() = %v", err)
+		}
+		if err = <-served; err != nil {
+			t.Errorf("ListenTLS() = %v", err)
+		}
+	})
+}
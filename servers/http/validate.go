@@ -0,0 +1,42 @@
+package http
+
+import (
+	"github.com/ok93-01-18/go-ms-lib/servers/http/internal/binding"
+)
+
+// ValidateTag - tag of validation rules, e.g. `validate:"required,min=1,max=100"`
+//
+// Rules:
+//
+//	required - value must not be zero, e.g. empty string, nil pointer or empty slice
+//	min=N - number must be at least N, length of string or slice must be at least N
+//	max=N - number must be at most N, length of string or slice must be at most N
+//	oneof=a b c - value must be one of space separated values
+//	regex=EXPR - string must match the regular expression, it must be the last rule as EXPR may contain commas
+//
+// Rules other than required are not checked for nil pointers, so optional fields should be pointers.
+// Zero values of other types are checked, e.g. absent int field violates min=1.
+// Nested structs, slices and maps of structs are validated recursively.
+const ValidateTag = binding.ValidateTag
+
+// FieldViolation - violation of validation rule by the field
+type FieldViolation = binding.FieldViolation
+
+// Validate validates the struct pointed by out by ValidateTag rules.
+// Violations are returned as StatusUnprocessableEntity HTTPError with []FieldViolation details,
+// fields are named by "json" tag.
+func Validate(out interface{}) error {
+	violations, err := binding.Validate(out, "json")
+	return validationError(violations, err)
+}
+
+// validationError returns violations as StatusUnprocessableEntity HTTPError.
+func validationError(violations []FieldViolation, err error) error {
+	if err != nil {
+		return err
+	}
+	if len(violations) > 0 {
+		return UnprocessableEntity("validation failed").WithDetails(violations)
+	}
+	return nil
+}
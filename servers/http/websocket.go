@@ -0,0 +1,591 @@
+package http
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	nethttp "net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// WebSocket message types, values are RFC 6455 opcodes
+const (
+	TextMessage   = 1
+	BinaryMessage = 2
+	CloseMessage  = 8
+	PingMessage   = 9
+	PongMessage   = 10
+)
+
+// WebSocket close codes, RFC 6455 7.4.1
+const (
+	CloseNormalClosure           = 1000
+	CloseGoingAway               = 1001
+	CloseProtocolError           = 1002
+	CloseUnsupportedData         = 1003
+	CloseNoStatusReceived        = 1005
+	CloseAbnormalClosure         = 1006
+	CloseInvalidFramePayloadData = 1007
+	ClosePolicyViolation         = 1008
+	CloseMessageTooBig           = 1009
+	CloseInternalServerErr       = 1011
+)
+
+const (
+	websocketGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+	// websocketCloseTimeout - time to wait for the peer reply to the close message
+	websocketCloseTimeout = time.Second
+
+	// websocketDefaultReadLimit - maximum size of messages until Conn.SetReadLimit is called
+	websocketDefaultReadLimit = 32 << 20
+
+	websocketContinuation     = 0
+	websocketMaxControlLength = 125
+)
+
+var (
+	// ErrCloseSent - returned by writes after the close message was sent
+	ErrCloseSent = errors.New("websocket: close sent")
+
+	// ErrInvalidMessageType - returned by WriteMessage for types other than text, binary, ping and pong
+	ErrInvalidMessageType = errors.New("websocket: invalid message type")
+)
+
+// CloseError - close message received from the peer or sent to it because of protocol violation
+type CloseError struct {
+	Code int
+	Text string
+}
+
+func (e *CloseError) Error() string {
+	if e.Text == "" {
+		return fmt.Sprintf("websocket: close %d", e.Code)
+	}
+	return fmt.Sprintf("websocket: close %d: %s", e.Code, e.Text)
+}
+
+// WebSocketHandler - handler of websocket connection, the connection is closed when the handler returns:
+// with CloseNormalClosure for nil error and CloseInternalServerErr otherwise.
+type WebSocketHandler func(conn Conn) error
+
+// Conn - websocket connection.
+// ReadMessage must not be called concurrently, writes are safe to use from multiple goroutines.
+type Conn interface {
+	// ReadMessage returns the next text or binary message, fragmented messages are joined.
+	// Ping messages are answered by pong, pong messages are passed to the pong handler.
+	// CloseError is returned when the peer closes the connection or violates the protocol,
+	// all errors are permanent and returned by subsequent calls.
+	ReadMessage() (messageType int, data []byte, err error)
+
+	// WriteMessage sends the message of TextMessage, BinaryMessage, PingMessage or PongMessage type.
+	WriteMessage(messageType int, data []byte) error
+
+	// Close sends the close message with the code and reason.
+	// The peer reply is read by ReadMessage, which returns CloseError within one second.
+	Close(code int, reason string) error
+
+	// SetPingHandler sets the handler of ping messages instead of the default one replying with pong.
+	SetPingHandler(func(data []byte) error)
+
+	// SetPongHandler sets the handler of pong messages, they are ignored by default.
+	SetPongHandler(func(data []byte) error)
+
+	// SetReadLimit sets the maximum size of the message, CloseMessageTooBig is sent for bigger messages.
+	// The default limit is 32 MiB, zero means no limit.
+	SetReadLimit(int64)
+
+	// SetReadDeadline sets the deadline of reads of the underlying connection.
+	SetReadDeadline(time.Time) error
+
+	// SetWriteDeadline sets the deadline of writes of the underlying connection.
+	SetWriteDeadline(time.Time) error
+
+	// Locals returns values set by handlers of the upgrade request, or sets the value for the connection.
+	Locals(string, ...interface{}) interface{}
+
+	// Params returns the route parameter of the upgrade request.
+	Params(key string, defaultValue ...string) string
+
+	// Query returns the query string parameter of the upgrade request.
+	Query(key string, defaultValue ...string) string
+
+	// IP returns the remote IP address of the upgrade request.
+	IP() string
+}
+
+// websocketFrame - frame read from the peer
+type websocketFrame struct {
+	fin     bool
+	opcode  int
+	payload []byte
+}
+
+// websocketConn - RFC 6455 connection over the hijacked connection of the server
+type websocketConn struct {
+	conn   net.Conn
+	reader *bufio.Reader
+
+	// readErr - permanent error of reads
+	readErr     error
+	readLimit   int64
+	pingHandler func([]byte) error
+	pongHandler func([]byte) error
+
+	writeMu   sync.Mutex
+	closeSent bool
+
+	localsMu sync.RWMutex
+	locals   map[string]interface{}
+	params   map[string]string
+	query    url.Values
+	ip       string
+}
+
+func (c *websocketConn) ReadMessage() (int, []byte, error) {
+	if c.readErr != nil {
+		return 0, nil, c.readErr
+	}
+
+	var messageType int
+	var message []byte
+	for {
+		limit := int64(-1)
+		if c.readLimit > 0 {
+			limit = c.readLimit - int64(len(message))
+		}
+
+		frame, err := c.readFrame(limit)
+		if err != nil {
+			return c.fail(err)
+		}
+
+		switch frame.opcode {
+		case PingMessage, PongMessage, CloseMessage:
+			err = c.handleControl(frame)
+			if err != nil {
+				return c.fail(err)
+			}
+			continue
+		case TextMessage, BinaryMessage:
+			if messageType != 0 {
+				return c.fail(&CloseError{Code: CloseProtocolError, Text: "unexpected data frame"})
+			}
+			messageType = frame.opcode
+		case websocketContinuation:
+			if messageType == 0 {
+				return c.fail(&CloseError{Code: CloseProtocolError, Text: "unexpected continuation frame"})
+			}
+		default:
+			return c.fail(&CloseError{Code: CloseProtocolError, Text: "unknown opcode"})
+		}
+
+		message = append(message, frame.payload...)
+		if !frame.fin {
+			continue
+		}
+
+		if messageType == TextMessage && !utf8.Valid(message) {
+			return c.fail(&CloseError{Code: CloseInvalidFramePayloadData, Text: "invalid UTF-8 text"})
+		}
+		return messageType, message, nil
+	}
+}
+
+// readFrame reads the next frame, payload of data frames longer than limit is not read, limit < 0 disables it.
+func (c *websocketConn) readFrame(limit int64) (*websocketFrame, error) {
+	var header [2]byte
+	_, err := io.ReadFull(c.reader, header[:])
+	if err != nil {
+		return nil, err
+	}
+
+	frame := &websocketFrame{fin: header[0]&0x80 != 0, opcode: int(header[0] & 0x0f)}
+	if header[0]&0x70 != 0 {
+		return nil, &CloseError{Code: CloseProtocolError, Text: "reserved bits are set"}
+	}
+	if header[1]&0x80 == 0 {
+		return nil, &CloseError{Code: CloseProtocolError, Text: "frame is not masked"}
+	}
+
+	length := int64(header[1] & 0x7f)
+	switch length {
+	case 126:
+		var ext [2]byte
+		_, err = io.ReadFull(c.reader, ext[:])
+		length = int64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		_, err = io.ReadFull(c.reader, ext[:])
+		length = int64(binary.BigEndian.Uint64(ext[:]))
+	}
+	if err != nil {
+		return nil, err
+	}
+	// the most significant bit of 64-bit length must be 0
+	if length < 0 {
+		return nil, &CloseError{Code: CloseProtocolError, Text: "invalid payload length"}
+	}
+
+	if frame.opcode >= CloseMessage {
+		if !frame.fin || length > websocketMaxControlLength {
+			return nil, &CloseError{Code: CloseProtocolError, Text: "invalid control frame"}
+		}
+	} else if limit >= 0 && length > limit {
+		return nil, &CloseError{Code: CloseMessageTooBig, Text: "message is too big"}
+	}
+
+	var mask [4]byte
+	_, err = io.ReadFull(c.reader, mask[:])
+	if err != nil {
+		return nil, err
+	}
+
+	frame.payload = make([]byte, length)
+	_, err = io.ReadFull(c.reader, frame.payload)
+	if err != nil {
+		return nil, err
+	}
+	for i := range frame.payload {
+		frame.payload[i] ^= mask[i%4]
+	}
+
+	return frame, nil
+}
+
+// handleControl handles ping, pong and close frames, CloseError is returned for the close frame.
+func (c *websocketConn) handleControl(frame *websocketFrame) error {
+	switch frame.opcode {
+	case PingMessage:
+		if c.pingHandler != nil {
+			return c.pingHandler(frame.payload)
+		}
+		err := c.WriteMessage(PongMessage, frame.payload)
+		if err == ErrCloseSent {
+			return nil
+		}
+		return err
+	case PongMessage:
+		if c.pongHandler != nil {
+			return c.pongHandler(frame.payload)
+		}
+		return nil
+	}
+
+	closeErr := &CloseError{Code: CloseNoStatusReceived}
+	if len(frame.payload) == 1 {
+		return &CloseError{Code: CloseProtocolError, Text: "invalid close payload"}
+	}
+	if len(frame.payload) >= 2 {
+		closeErr.Code = int(binary.BigEndian.Uint16(frame.payload))
+		closeErr.Text = string(frame.payload[2:])
+		if !validCloseCode(closeErr.Code) {
+			return &CloseError{Code: CloseProtocolError, Text: "invalid close code"}
+		}
+		if !utf8.ValidString(closeErr.Text) {
+			return &CloseError{Code: CloseInvalidFramePayloadData, Text: "invalid UTF-8 close reason"}
+		}
+	}
+
+	// the close message is echoed as the reply
+	_ = c.writeClose(closeErr.Code, "")
+	return closeErr
+}
+
+// fail makes the read error permanent, the close message is sent to the peer for protocol violations.
+func (c *websocketConn) fail(err error) (int, []byte, error) {
+	var closeErr *CloseError
+	if errors.As(err, &closeErr) {
+		_ = c.writeClose(closeErr.Code, closeErr.Text)
+	} else if err == io.EOF || err == io.ErrUnexpectedEOF {
+		err = &CloseError{Code: CloseAbnormalClosure, Text: "unexpected EOF"}
+	}
+
+	c.readErr = err
+	return 0, nil, err
+}
+
+func (c *websocketConn) WriteMessage(messageType int, data []byte) error {
+	switch messageType {
+	case TextMessage, BinaryMessage:
+	case PingMessage, PongMessage:
+		if len(data) > websocketMaxControlLength {
+			return errors.New("websocket: control message is too big")
+		}
+	default:
+		return ErrInvalidMessageType
+	}
+
+	return c.writeFrame(messageType, data)
+}
+
+func (c *websocketConn) Close(code int, reason string) error {
+	err := c.writeClose(code, reason)
+	if err != nil {
+		return err
+	}
+	return c.conn.SetReadDeadline(time.Now().Add(websocketCloseTimeout))
+}
+
+// writeClose sends the close message once, CloseNoStatusReceived is sent without the code.
+func (c *websocketConn) writeClose(code int, reason string) error {
+	var payload []byte
+	if code != CloseNoStatusReceived && code != CloseAbnormalClosure {
+		if len(reason) > websocketMaxControlLength-2 {
+			reason = reason[:websocketMaxControlLength-2]
+		}
+		payload = make([]byte, 2, 2+len(reason))
+		binary.BigEndian.PutUint16(payload, uint16(code))
+		payload = append(payload, reason...)
+	}
+
+	return c.writeFrame(CloseMessage, payload)
+}
+
+// writeFrame writes the message as a single unmasked frame.
+func (c *websocketConn) writeFrame(opcode int, payload []byte) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	if c.closeSent {
+		return ErrCloseSent
+	}
+	if opcode == CloseMessage {
+		c.closeSent = true
+	}
+
+	header := make([]byte, 2, 10)
+	header[0] = 0x80 | byte(opcode)
+	switch length := len(payload); {
+	case length <= websocketMaxControlLength:
+		header[1] = byte(length)
+	case length <= 0xffff:
+		header[1] = 126
+		header = header[:4]
+		binary.BigEndian.PutUint16(header[2:], uint16(length))
+	default:
+		header[1] = 127
+		header = header[:10]
+		binary.BigEndian.PutUint64(header[2:], uint64(length))
+	}
+
+	buffers := net.Buffers{header, payload}
+	_, err := buffers.WriteTo(c.conn)
+	return err
+}
+
+func (c *websocketConn) SetPingHandler(handler func(data []byte) error) {
+	c.pingHandler = handler
+}
+
+func (c *websocketConn) SetPongHandler(handler func(data []byte) error) {
+	c.pongHandler = handler
+}
+
+func (c *websocketConn) SetReadLimit(limit int64) {
+	c.readLimit = limit
+}
+
+func (c *websocketConn) SetReadDeadline(t time.Time) error {
+	return c.conn.SetReadDeadline(t)
+}
+
+func (c *websocketConn) SetWriteDeadline(t time.Time) error {
+	return c.conn.SetWriteDeadline(t)
+}
+
+func (c *websocketConn) Locals(key string, value ...interface{}) interface{} {
+	if len(value) == 0 {
+		c.localsMu.RLock()
+		defer c.localsMu.RUnlock()
+		return c.locals[key]
+	}
+
+	c.localsMu.Lock()
+	c.locals[key] = value[0]
+	c.localsMu.Unlock()
+	return value[0]
+}
+
+func (c *websocketConn) Params(key string, defaultValue ...string) string {
+	return defaultString(c.params[key], defaultValue)
+}
+
+func (c *websocketConn) Query(key string, defaultValue ...string) string {
+	return defaultString(c.query.Get(key), defaultValue)
+}
+
+func (c *websocketConn) IP() string {
+	return c.ip
+}
+
+// finish sends the close message for the handler result and closes the connection.
+func (c *websocketConn) finish(err error) {
+	code := CloseNormalClosure
+	var closeErr *CloseError
+	if err != nil && !errors.As(err, &closeErr) {
+		code = CloseInternalServerErr
+	}
+
+	_ = c.conn.SetWriteDeadline(time.Now().Add(websocketCloseTimeout))
+	_ = c.writeClose(code, "")
+	_ = c.conn.Close()
+}
+
+// validCloseCode checks if the close code can be received from the peer.
+func validCloseCode(code int) bool {
+	switch {
+	case code >= 3000 && code <= 4999:
+		return true
+	case code < CloseNormalClosure || code > 1014:
+		return false
+	}
+	return code != 1004 && code != CloseNoStatusReceived && code != CloseAbnormalClosure
+}
+
+// websocketRequest - data of the upgrade request available on the connection
+type websocketRequest struct {
+	params map[string]string
+	query  url.Values
+	locals map[string]interface{}
+	ip     string
+}
+
+func newWebsocketConn(conn net.Conn, reader *bufio.Reader, req *websocketRequest) *websocketConn {
+	// deadlines of the server are not applied to the hijacked connection
+	_ = conn.SetDeadline(time.Time{})
+
+	return &websocketConn{
+		conn:      conn,
+		reader:    reader,
+		readLimit: websocketDefaultReadLimit,
+		locals:    req.locals,
+		params:    req.params,
+		query:     req.query,
+		ip:        req.ip,
+	}
+}
+
+// websocketAccept validates the upgrade request and returns the Sec-WebSocket-Accept value.
+func websocketAccept(ctx Context) (string, error) {
+	if !headerHasToken(ctx.Get("Connection"), "upgrade") || !headerHasToken(ctx.Get("Upgrade"), "websocket") {
+		ctx.Set("Upgrade", "websocket")
+		return "", NewError(StatusUpgradeRequired)
+	}
+	if ctx.Method() != nethttp.MethodGet {
+		return "", MethodNotAllowed()
+	}
+	if ctx.Get("Sec-WebSocket-Version") != "13" {
+		ctx.Set("Sec-WebSocket-Version", "13")
+		return "", BadRequest("websocket: unsupported version")
+	}
+
+	key := ctx.Get("Sec-WebSocket-Key")
+	decoded, err := base64.StdEncoding.DecodeString(key)
+	if err != nil || len(decoded) != 16 {
+		return "", BadRequest("websocket: invalid Sec-WebSocket-Key")
+	}
+
+	sum := sha1.Sum([]byte(key + websocketGUID))
+	return base64.StdEncoding.EncodeToString(sum[:]), nil
+}
+
+// headerHasToken checks if the comma-separated header value contains the token case-insensitively.
+func headerHasToken(value, token string) bool {
+	for _, v := range strings.Split(value, ",") {
+		if strings.EqualFold(strings.TrimSpace(v), token) {
+			return true
+		}
+	}
+	return false
+}
+
+// websockets - websocket connections of the server, which are closed on shutdown
+type websockets struct {
+	mu      sync.Mutex
+	conns   map[*websocketConn]struct{}
+	wg      sync.WaitGroup
+	closing bool
+}
+
+// serve runs the handler on the connection and closes it afterwards,
+// connections upgraded after shutdown started are closed with CloseGoingAway.
+// Panic of the handler closes the connection with CloseInternalServerErr.
+func (w *websockets) serve(conn *websocketConn, handler WebSocketHandler) {
+	w.mu.Lock()
+	if w.closing {
+		w.mu.Unlock()
+		_ = conn.writeClose(CloseGoingAway, "server shutdown")
+		conn.finish(nil)
+		return
+	}
+	if w.conns == nil {
+		w.conns = make(map[*websocketConn]struct{})
+	}
+	w.conns[conn] = struct{}{}
+	w.wg.Add(1)
+	w.mu.Unlock()
+
+	defer func() {
+		w.mu.Lock()
+		delete(w.conns, conn)
+		w.mu.Unlock()
+		w.wg.Done()
+	}()
+
+	conn.finish(runWebSocketHandler(handler, conn))
+}
+
+// runWebSocketHandler runs the handler and returns panic as error,
+// as the hijacked connection may be served outside of the request goroutine.
+func runWebSocketHandler(handler WebSocketHandler, conn Conn) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("websocket: handler panic: %v", r)
+		}
+	}()
+	return handler(conn)
+}
+
+// shutdown sends the close message with CloseGoingAway to connections and waits for their handlers,
+// remaining connections are closed forcibly when the context is done.
+func (w *websockets) shutdown(ctx context.Context) error {
+	w.mu.Lock()
+	w.closing = true
+	conns := make([]*websocketConn, 0, len(w.conns))
+	for conn := range w.conns {
+		conns = append(conns, conn)
+	}
+	w.mu.Unlock()
+
+	for _, conn := range conns {
+		_ = conn.conn.SetWriteDeadline(time.Now().Add(websocketCloseTimeout))
+		_ = conn.Close(CloseGoingAway, "server shutdown")
+	}
+
+	done := make(chan struct{})
+	go func() {
+		w.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		for _, conn := range conns {
+			_ = conn.conn.Close()
+		}
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,203 @@
+package http_test
+
+import (
+	"bufio"
+	"encoding/binary"
+	"github.com/ok93-01-18/go-ms-lib/servers/http"
+	"github.com/ok93-01-18/go-ms-lib/servers/http/httpassert"
+	"github.com/ok93-01-18/go-ms-lib/servers/http/internal/servertest"
+	"io"
+	"net"
+	nethttp "net/http"
+	"testing"
+	"time"
+)
+
+// wsClient - minimal websocket client writing masked frames as is
+type wsClient struct {
+	t      *testing.T
+	conn   net.Conn
+	reader *bufio.Reader
+}
+
+func dialWebSocket(t *testing.T, addr, path string) *wsClient {
+	t.Helper()
+
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatalf("Dial() = %v", err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	_ = conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+	_, err = io.WriteString(conn, "GET "+path+" HTTP/1.1\r\n"+
+		"Host: "+addr+"\r\n"+
+		"Connection: Upgrade\r\n"+
+		"Upgrade: websocket\r\n"+
+		"Sec-WebSocket-Version: 13\r\n"+
+		"Sec-WebSocket-Key: dGhlIHNhbXBsZSBub25jZQ==\r\n\r\n")
+	if err != nil {
+		t.Fatalf("write upgrade request: %v", err)
+	}
+
+	reader := bufio.NewReader(conn)
+	resp, err := nethttp.ReadResponse(reader, nil)
+	if err != nil {
+		t.Fatalf("read upgrade response: %v", err)
+	}
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		t.Fatalf("upgrade status = %d, want %d", resp.StatusCode, http.StatusSwitchingProtocols)
+	}
+	if got := resp.Header.Get("Sec-WebSocket-Accept"); got != "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=" {
+		t.Errorf("Sec-WebSocket-Accept = %q", got)
+	}
+
+	return &wsClient{t: t, conn: conn, reader: reader}
+}
+
+// writeHeader writes the frame header with the length field and zero mask.
+func (c *wsClient) writeHeader(opcode int, length uint64) {
+	c.t.Helper()
+
+	header := []byte{0x80 | byte(opcode), 0x80 | 127, 0, 0, 0, 0, 0, 0, 0, 0}
+	binary.BigEndian.PutUint64(header[2:], length)
+	header = append(header, 0, 0, 0, 0)
+	if _, err := c.conn.Write(header); err != nil {
+		c.t.Fatalf("write frame header: %v", err)
+	}
+}
+
+func (c *wsClient) write(opcode int, payload string) {
+	c.t.Helper()
+
+	c.writeHeader(opcode, uint64(len(payload)))
+	if _, err := io.WriteString(c.conn, payload); err != nil {
+		c.t.Fatalf("write frame payload: %v", err)
+	}
+}
+
+// read reads the next frame, the payload of close frames is returned without the code.
+func (c *wsClient) read() (opcode int, payload string, code int) {
+	c.t.Helper()
+
+	var header [2]byte
+	if _, err := io.ReadFull(c.reader, header[:]); err != nil {
+		c.t.Fatalf("read frame header: %v", err)
+	}
+
+	length := int(header[1] & 0x7f)
+	if length == 126 {
+		var ext [2]byte
+		_, _ = io.ReadFull(c.reader, ext[:])
+		length = int(binary.BigEndian.Uint16(ext[:]))
+	}
+	data := make([]byte, length)
+	if _, err := io.ReadFull(c.reader, data); err != nil {
+		c.t.Fatalf("read frame payload: %v", err)
+	}
+
+	opcode = int(header[0] & 0x0f)
+	if opcode == http.CloseMessage && len(data) >= 2 {
+		return opcode, string(data[2:]), int(binary.BigEndian.Uint16(data))
+	}
+	return opcode, string(data), 0
+}
+
+func (c *wsClient) expectClose(code int) {
+	c.t.Helper()
+
+	opcode, reason, got := c.read()
+	if opcode != http.CloseMessage || got != code {
+		c.t.Errorf("frame = %d %d %q, want close %d", opcode, got, reason, code)
+	}
+}
+
+func serveEcho(t *testing.T, server http.Server) string {
+	server.WebSocket("/ws", func(conn http.Conn) error {
+		for {
+			messageType, message, err := conn.ReadMessage()
+			if err != nil {
+				return err
+			}
+			if string(message) == "panic" {
+				panic("echo panic")
+			}
+			if err = conn.WriteMessage(messageType, message); err != nil {
+				return err
+			}
+		}
+	})
+	return servertest.Serve(t, server)
+}
+
+func TestWebSocket_Echo(t *testing.T) {
+	servertest.Run(t, func(t *testing.T, server http.Server) {
+		client := dialWebSocket(t, serveEcho(t, server), "/ws")
+
+		client.write(http.TextMessage, "hello")
+		if opcode, payload, _ := client.read(); opcode != http.TextMessage || payload != "hello" {
+			t.Errorf("frame = %d %q, want text hello", opcode, payload)
+		}
+
+		client.write(http.PingMessage, "ping")
+		if opcode, payload, _ := client.read(); opcode != http.PongMessage || payload != "ping" {
+			t.Errorf("frame = %d %q, want pong ping", opcode, payload)
+		}
+
+		client.write(http.CloseMessage, "\x03\xe8")
+		client.expectClose(http.CloseNormalClosure)
+	})
+}
+
+func TestWebSocket_UpgradeRequired(t *testing.T) {
+	servertest.Run(t, func(t *testing.T, server http.Server) {
+		server.WebSocket("/ws", func(conn http.Conn) error {
+			return nil
+		})
+
+		httpassert.Do(t, server, httpassert.NewRequest(nethttp.MethodGet, "/ws", nil)).
+			Status(http.StatusUpgradeRequired).
+			Header("Upgrade", "websocket")
+	})
+}
+
+func TestWebSocket_InvalidLength(t *testing.T) {
+	servertest.Run(t, func(t *testing.T, server http.Server) {
+		addr := serveEcho(t, server)
+
+		// 64-bit length with the most significant bit set
+		client := dialWebSocket(t, addr, "/ws")
+		client.writeHeader(http.BinaryMessage, 1<<63)
+		client.expectClose(http.CloseProtocolError)
+
+		client = dialWebSocket(t, addr, "/ws")
+		client.writeHeader(http.PingMessage, 1<<63)
+		client.expectClose(http.CloseProtocolError)
+	})
+}
+
+func TestWebSocket_DefaultReadLimit(t *testing.T) {
+	servertest.Run(t, func(t *testing.T, server http.Server) {
+		client := dialWebSocket(t, serveEcho(t, server), "/ws")
+
+		client.writeHeader(http.BinaryMessage, 64<<20)
+		client.expectClose(http.CloseMessageTooBig)
+	})
+}
+
+func TestWebSocket_HandlerPanic(t *testing.T) {
+	servertest.Run(t, func(t *testing.T, server http.Server) {
+		addr := serveEcho(t, server)
+
+		client := dialWebSocket(t, addr, "/ws")
+		client.write(http.TextMessage, "panic")
+		client.expectClose(http.CloseInternalServerErr)
+
+		// the server keeps serving other connections
+		client = dialWebSocket(t, addr, "/ws")
+		client.write(http.TextMessage, "hello")
+		if opcode, payload, _ := client.read(); opcode != http.TextMessage || payload != "hello" {
+			t.Errorf("frame = %d %q, want text hello", opcode, payload)
+		}
+	})
+}
@@ -0,0 +1,53 @@
+package tracing
+
+import (
+	"context"
+	"sync"
+)
+
+// Exporter - destination of ended spans
+type Exporter interface {
+	// Export sends the batch of spans, it is not called concurrently.
+	Export(ctx context.Context, spans []SpanData) error
+
+	// Shutdown releases resources of the exporter, Export is not called after it.
+	Shutdown(ctx context.Context) error
+}
+
+// MemoryExporter - exporter which keeps spans in memory, e.g. for tests
+type MemoryExporter struct {
+	mu    sync.Mutex
+	spans []SpanData
+}
+
+func (e *MemoryExporter) Export(ctx context.Context, spans []SpanData) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.spans = append(e.spans, spans...)
+	return nil
+}
+
+func (e *MemoryExporter) Shutdown(ctx context.Context) error {
+	return nil
+}
+
+// Spans returns copy of exported spans in order of export.
+func (e *MemoryExporter) Spans() []SpanData {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return append([]SpanData(nil), e.spans...)
+}
+
+// Reset removes exported spans.
+func (e *MemoryExporter) Reset() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.spans = nil
+}
+
+func NewMemoryExporter() *MemoryExporter {
+	return &MemoryExporter{}
+}
@@ -0,0 +1,85 @@
+package tracing
+
+import (
+	"context"
+	"github.com/ok93-01-18/go-ms-lib/servers/http"
+	nethttp "net/http"
+)
+
+// LocalsKey - key of the request span in Context.Locals
+const LocalsKey = "span"
+
+type HTTPConfig struct {
+	// Tracer starts request spans.
+	Tracer *Tracer
+
+	// Skip - spans are not started if it returns true, e.g. for health probes.
+	Skip func(http.Context) bool
+}
+
+// NewHTTPMiddleware - return middleware which starts server span per request.
+// Span continues the trace of traceparent request header or starts new trace, if the header is absent or invalid.
+// It is named by the method and the route path once the request is handled, e.g. "GET /users/:id".
+// Span context is sent back in traceparent response header, so clients can find the trace of the request.
+func NewHTTPMiddleware(conf *HTTPConfig) http.Handler {
+	return func(ctx http.Context) error {
+		if conf.Skip != nil && conf.Skip(ctx) {
+			return ctx.Next()
+		}
+
+		parent, err := ParseTraceparent(ctx.Get(HeaderTraceparent))
+		if err == nil {
+			parent.TraceState = ctx.Get(HeaderTracestate)
+		}
+
+		method := ctx.Method()
+		span := conf.Tracer.StartSpan(method, SpanKindServer, parent)
+		span.SetAttribute("http.method", method)
+		span.SetAttribute("http.scheme", ctx.Request().Scheme())
+		span.SetAttribute("http.target", ctx.Request().RequestURI())
+		span.SetAttribute("http.flavor", ctx.Request().Protocol())
+		span.SetAttribute("net.peer.ip", ctx.IP())
+		if ua := ctx.Get("User-Agent"); ua != "" {
+			span.SetAttribute("http.user_agent", ua)
+		}
+
+		ctx.Locals(LocalsKey, span)
+		ctx.Set(HeaderTraceparent, span.Context().Traceparent())
+
+		err = ctx.Next()
+
+		route := ctx.Route().Path
+		status := ctx.Response().StatusCode()
+		if err != nil {
+			status = http.ErrorStatusCode(err)
+		}
+
+		span.SetName(method + " " + route)
+		span.SetAttribute("http.route", route)
+		span.SetAttribute("http.status_code", status)
+		if err != nil && status >= http.StatusInternalServerError {
+			span.SetStatus(StatusError, err.Error())
+		} else if status >= http.StatusInternalServerError {
+			span.SetStatus(StatusError, nethttp.StatusText(status))
+		}
+		span.End()
+
+		return err
+	}
+}
+
+// FromContext returns span of the request, nil is returned if the middleware was not applied.
+func FromContext(ctx http.Context) *Span {
+	span, _ := ctx.Locals(LocalsKey).(*Span)
+	return span
+}
+
+// NewContext returns context of the request with its span, spans started with it become children of the request span.
+// The context is derived from Context.Context, so downstream calls are canceled with the request.
+func NewContext(ctx http.Context) context.Context {
+	span := FromContext(ctx)
+	if span == nil {
+		return ctx.Context()
+	}
+	return ContextWithSpan(ctx.Context(), span)
+}
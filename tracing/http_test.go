@@ -0,0 +1,36 @@
+package tracing_test
+
+import (
+	"context"
+	"github.com/ok93-01-18/go-ms-lib/servers/http"
+	"github.com/ok93-01-18/go-ms-lib/servers/http/httptest"
+	"github.com/ok93-01-18/go-ms-lib/tracing"
+	nethttp "net/http"
+	"testing"
+)
+
+func TestNewContext(t *testing.T) {
+	exportedSpans(t, func(tracer *tracing.Tracer) {
+		requestCtx, cancel := context.WithCancel(context.Background())
+		req := httptest.NewRequest(nethttp.MethodGet, "/").WithHeader(tracing.HeaderTraceparent, traceparent).WithContext(requestCtx)
+
+		ctx := httptest.NewContext(req).WithNext(func(ctx http.Context) error {
+			spanCtx := tracing.NewContext(ctx)
+			if span := tracing.SpanFromContext(spanCtx); span == nil || span.Context().TraceID.String() != traceID {
+				t.Errorf("NewContext() span = %+v, want span of trace %s", span, traceID)
+			}
+
+			// downstream calls are canceled with the request
+			cancel()
+			select {
+			case <-spanCtx.Done():
+			default:
+				t.Error("NewContext() isn't canceled with the request")
+			}
+			return nil
+		})
+		if err := tracing.NewHTTPMiddleware(&tracing.HTTPConfig{Tracer: tracer})(ctx); err != nil {
+			t.Errorf("middleware = %v", err)
+		}
+	})
+}
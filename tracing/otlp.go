@@ -0,0 +1,241 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	nethttp "net/http"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// Defaults used for zero values of OTLPConfig
+const (
+	DefaultOTLPEndpoint = "http://localhost:4318/v1/traces"
+	DefaultOTLPTimeout  = 10 * time.Second
+)
+
+// scopeName - instrumentation scope of exported spans
+const scopeName = "github.com/ok93-01-18/go-ms-lib/tracing"
+
+type OTLPConfig struct {
+	// Endpoint - URL of OTLP/HTTP traces receiver, DefaultOTLPEndpoint is used when empty.
+	Endpoint string
+
+	// Headers are added to export requests, e.g. authorization.
+	Headers map[string]string
+
+	// Timeout of export request, DefaultOTLPTimeout is used when zero.
+	Timeout time.Duration
+
+	// ServiceName - service.name resource attribute.
+	ServiceName string
+
+	// ResourceAttributes - additional attributes of the resource, e.g. deployment.environment.
+	ResourceAttributes map[string]interface{}
+
+	// Client sends export requests, client with Timeout is used when nil.
+	Client *nethttp.Client
+}
+
+// OTLPExporter - exporter which sends spans to OTLP/HTTP receiver with JSON encoding
+type OTLPExporter struct {
+	conf     OTLPConfig
+	resource otlpResource
+}
+
+func (e *OTLPExporter) Export(ctx context.Context, spans []SpanData) error {
+	body, err := json.Marshal(e.request(spans))
+	if err != nil {
+		return err
+	}
+
+	req, err := nethttp.NewRequestWithContext(ctx, nethttp.MethodPost, e.conf.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range e.conf.Headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := e.conf.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("tracing: otlp receiver responded %d: %s", resp.StatusCode, bytes.TrimSpace(msg))
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	return nil
+}
+
+func (e *OTLPExporter) Shutdown(ctx context.Context) error {
+	e.conf.Client.CloseIdleConnections()
+	return nil
+}
+
+func (e *OTLPExporter) request(spans []SpanData) *otlpRequest {
+	otlpSpans := make([]otlpSpan, 0, len(spans))
+	for _, span := range spans {
+		s := otlpSpan{
+			TraceID:           span.Context.TraceID.String(),
+			SpanID:            span.Context.SpanID.String(),
+			TraceState:        span.Context.TraceState,
+			Name:              span.Name,
+			Kind:              int(span.Kind),
+			StartTimeUnixNano: strconv.FormatInt(span.Start.UnixNano(), 10),
+			EndTimeUnixNano:   strconv.FormatInt(span.End.UnixNano(), 10),
+			Attributes:        otlpAttributes(span.Attributes),
+			Status:            otlpStatus{Code: int(span.Status), Message: span.StatusMessage},
+		}
+		if span.Parent.IsValid() {
+			s.ParentSpanID = span.Parent.String()
+		}
+		otlpSpans = append(otlpSpans, s)
+	}
+
+	return &otlpRequest{
+		ResourceSpans: []otlpResourceSpans{{
+			Resource: e.resource,
+			ScopeSpans: []otlpScopeSpans{{
+				Scope: otlpScope{Name: scopeName},
+				Spans: otlpSpans,
+			}},
+		}},
+	}
+}
+
+// NewOTLPExporter - return exporter to OTLP/HTTP receiver, e.g. OpenTelemetry Collector
+func NewOTLPExporter(conf *OTLPConfig) *OTLPExporter {
+	c := *conf
+	if c.Endpoint == "" {
+		c.Endpoint = DefaultOTLPEndpoint
+	}
+	if c.Timeout == 0 {
+		c.Timeout = DefaultOTLPTimeout
+	}
+	if c.Client == nil {
+		c.Client = &nethttp.Client{Timeout: c.Timeout}
+	}
+
+	attributes := make(map[string]interface{}, len(c.ResourceAttributes)+1)
+	for key, value := range c.ResourceAttributes {
+		attributes[key] = value
+	}
+	if c.ServiceName != "" {
+		attributes["service.name"] = c.ServiceName
+	}
+
+	return &OTLPExporter{
+		conf:     c,
+		resource: otlpResource{Attributes: otlpAttributes(attributes)},
+	}
+}
+
+// OTLP JSON encoding, see opentelemetry-proto trace/v1 and common/v1
+
+type otlpRequest struct {
+	ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+}
+
+type otlpResourceSpans struct {
+	Resource   otlpResource     `json:"resource"`
+	ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+}
+
+type otlpResource struct {
+	Attributes []otlpKeyValue `json:"attributes,omitempty"`
+}
+
+type otlpScopeSpans struct {
+	Scope otlpScope  `json:"scope"`
+	Spans []otlpSpan `json:"spans"`
+}
+
+type otlpScope struct {
+	Name string `json:"name"`
+}
+
+type otlpSpan struct {
+	TraceID           string         `json:"traceId"`
+	SpanID            string         `json:"spanId"`
+	TraceState        string         `json:"traceState,omitempty"`
+	ParentSpanID      string         `json:"parentSpanId,omitempty"`
+	Name              string         `json:"name"`
+	Kind              int            `json:"kind"`
+	StartTimeUnixNano string         `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string         `json:"endTimeUnixNano"`
+	Attributes        []otlpKeyValue `json:"attributes,omitempty"`
+	Status            otlpStatus     `json:"status"`
+}
+
+type otlpStatus struct {
+	Code    int    `json:"code,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+type otlpKeyValue struct {
+	Key   string       `json:"key"`
+	Value otlpAnyValue `json:"value"`
+}
+
+type otlpAnyValue struct {
+	StringValue *string  `json:"stringValue,omitempty"`
+	BoolValue   *bool    `json:"boolValue,omitempty"`
+	IntValue    *string  `json:"intValue,omitempty"`
+	DoubleValue *float64 `json:"doubleValue,omitempty"`
+}
+
+// otlpAttributes converts attributes sorted by key, unsupported values are converted to strings.
+func otlpAttributes(attributes map[string]interface{}) []otlpKeyValue {
+	keys := make([]string, 0, len(attributes))
+	for key := range attributes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	kvs := make([]otlpKeyValue, 0, len(keys))
+	for _, key := range keys {
+		kvs = append(kvs, otlpKeyValue{Key: key, Value: otlpValue(attributes[key])})
+	}
+	return kvs
+}
+
+func otlpValue(value interface{}) otlpAnyValue {
+	var v otlpAnyValue
+	switch value := value.(type) {
+	case string:
+		v.StringValue = &value
+	case bool:
+		v.BoolValue = &value
+	case int:
+		s := strconv.FormatInt(int64(value), 10)
+		v.IntValue = &s
+	case int32:
+		s := strconv.FormatInt(int64(value), 10)
+		v.IntValue = &s
+	case int64:
+		s := strconv.FormatInt(value, 10)
+		v.IntValue = &s
+	case uint32:
+		s := strconv.FormatUint(uint64(value), 10)
+		v.IntValue = &s
+	case float32:
+		f := float64(value)
+		v.DoubleValue = &f
+	case float64:
+		v.DoubleValue = &value
+	default:
+		s := fmt.Sprint(value)
+		v.StringValue = &s
+	}
+	return v
+}
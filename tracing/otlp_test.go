@@ -0,0 +1,177 @@
+package tracing_test
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/ok93-01-18/go-ms-lib/tracing"
+	"io"
+	nethttp "net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+// exportedSpans returns spans ended within the function, they are recorded by the in-memory exporter.
+func exportedSpans(t *testing.T, f func(tracer *tracing.Tracer)) []tracing.SpanData {
+	t.Helper()
+
+	exporter := tracing.NewMemoryExporter()
+	tracer := tracing.NewTracer(&tracing.Config{Exporter: exporter})
+	f(tracer)
+	if err := tracer.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown() = %v", err)
+	}
+	return exporter.Spans()
+}
+
+func TestOTLPExporter_Export(t *testing.T) {
+	spans := exportedSpans(t, func(tracer *tracing.Tracer) {
+		ctx, parent := tracer.Start(context.Background(), "parent", tracing.SpanKindServer)
+		_, child := tracer.Start(ctx, "child", tracing.SpanKindClient)
+		child.SetAttribute("http.status_code", 200)
+		child.SetAttribute("peer.service", "users")
+		child.SetAttribute("retry", true)
+		child.SetAttribute("ratio", 0.5)
+		child.SetStatus(tracing.StatusError, "failed")
+		child.End()
+		parent.End()
+	})
+	if len(spans) != 2 {
+		t.Fatalf("exported %d spans, want 2", len(spans))
+	}
+
+	var body []byte
+	receiver := httptest.NewServer(nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		if got := r.Header.Get("Content-Type"); got != "application/json" {
+			t.Errorf("Content-Type = %q, want application/json", got)
+		}
+		if got := r.Header.Get("Authorization"); got != "Bearer token" {
+			t.Errorf("Authorization = %q, want Bearer token", got)
+		}
+		body, _ = io.ReadAll(r.Body)
+	}))
+	defer receiver.Close()
+
+	exporter := tracing.NewOTLPExporter(&tracing.OTLPConfig{
+		Endpoint:           receiver.URL,
+		Headers:            map[string]string{"Authorization": "Bearer token"},
+		ServiceName:        "api",
+		ResourceAttributes: map[string]interface{}{"deployment.environment": "test"},
+	})
+	if err := exporter.Export(context.Background(), spans); err != nil {
+		t.Fatalf("Export() = %v", err)
+	}
+
+	type keyValue struct {
+		Key   string                 `json:"key"`
+		Value map[string]interface{} `json:"value"`
+	}
+	var payload struct {
+		ResourceSpans []struct {
+			Resource struct {
+				Attributes []keyValue `json:"attributes"`
+			} `json:"resource"`
+			ScopeSpans []struct {
+				Scope struct {
+					Name string `json:"name"`
+				} `json:"scope"`
+				Spans []struct {
+					TraceID           string     `json:"traceId"`
+					SpanID            string     `json:"spanId"`
+					ParentSpanID      string     `json:"parentSpanId"`
+					Name              string     `json:"name"`
+					Kind              int        `json:"kind"`
+					StartTimeUnixNano string     `json:"startTimeUnixNano"`
+					EndTimeUnixNano   string     `json:"endTimeUnixNano"`
+					Attributes        []keyValue `json:"attributes"`
+					Status            struct {
+						Code    int    `json:"code"`
+						Message string `json:"message"`
+					} `json:"status"`
+				} `json:"spans"`
+			} `json:"scopeSpans"`
+		} `json:"resourceSpans"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		t.Fatalf("decode payload %s: %v", body, err)
+	}
+	if len(payload.ResourceSpans) != 1 || len(payload.ResourceSpans[0].ScopeSpans) != 1 {
+		t.Fatalf("payload = %s, want one resource and scope", body)
+	}
+
+	resource := payload.ResourceSpans[0].Resource.Attributes
+	wantResource := []keyValue{
+		{Key: "deployment.environment", Value: map[string]interface{}{"stringValue": "test"}},
+		{Key: "service.name", Value: map[string]interface{}{"stringValue": "api"}},
+	}
+	if !equalJSON(t, resource, wantResource) {
+		t.Errorf("resource attributes = %+v, want %+v", resource, wantResource)
+	}
+
+	otlpSpans := payload.ResourceSpans[0].ScopeSpans[0].Spans
+	if len(otlpSpans) != len(spans) {
+		t.Fatalf("payload has %d spans, want %d", len(otlpSpans), len(spans))
+	}
+	for i, span := range spans {
+		got := otlpSpans[i]
+		if got.TraceID != span.Context.TraceID.String() || got.SpanID != span.Context.SpanID.String() {
+			t.Errorf("span %s IDs = %s %s, want %s %s", span.Name, got.TraceID, got.SpanID, span.Context.TraceID, span.Context.SpanID)
+		}
+		if got.Name != span.Name || got.Kind != int(span.Kind) {
+			t.Errorf("span %s = %s kind %d, want kind %d", span.Name, got.Name, got.Kind, span.Kind)
+		}
+		if got.StartTimeUnixNano != strconv.FormatInt(span.Start.UnixNano(), 10) ||
+			got.EndTimeUnixNano != strconv.FormatInt(span.End.UnixNano(), 10) {
+			t.Errorf("span %s times = %s %s", span.Name, got.StartTimeUnixNano, got.EndTimeUnixNano)
+		}
+		if got.Status.Code != int(span.Status) || got.Status.Message != span.StatusMessage {
+			t.Errorf("span %s status = %+v, want %d %q", span.Name, got.Status, span.Status, span.StatusMessage)
+		}
+
+		switch span.Name {
+		case "parent":
+			if got.ParentSpanID != "" {
+				t.Errorf("root span parentSpanId = %q, want empty", got.ParentSpanID)
+			}
+		case "child":
+			if got.ParentSpanID != span.Parent.String() {
+				t.Errorf("child parentSpanId = %q, want %q", got.ParentSpanID, span.Parent)
+			}
+			wantAttributes := []keyValue{
+				{Key: "http.status_code", Value: map[string]interface{}{"intValue": "200"}},
+				{Key: "peer.service", Value: map[string]interface{}{"stringValue": "users"}},
+				{Key: "ratio", Value: map[string]interface{}{"doubleValue": 0.5}},
+				{Key: "retry", Value: map[string]interface{}{"boolValue": true}},
+			}
+			if !equalJSON(t, got.Attributes, wantAttributes) {
+				t.Errorf("child attributes = %+v, want %+v", got.Attributes, wantAttributes)
+			}
+		}
+	}
+}
+
+func TestOTLPExporter_ExportFailed(t *testing.T) {
+	receiver := httptest.NewServer(nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		nethttp.Error(w, "unavailable", nethttp.StatusServiceUnavailable)
+	}))
+	defer receiver.Close()
+
+	exporter := tracing.NewOTLPExporter(&tracing.OTLPConfig{Endpoint: receiver.URL})
+	if err := exporter.Export(context.Background(), nil); err == nil {
+		t.Error("Export() to failing receiver returned no error")
+	}
+}
+
+func equalJSON(t *testing.T, a, b interface{}) bool {
+	t.Helper()
+
+	encodedA, err := json.Marshal(a)
+	if err != nil {
+		t.Fatalf("Marshal() = %v", err)
+	}
+	encodedB, err := json.Marshal(b)
+	if err != nil {
+		t.Fatalf("Marshal() = %v", err)
+	}
+	return string(encodedA) == string(encodedB)
+}
@@ -0,0 +1,163 @@
+package tracing
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	nethttp "net/http"
+)
+
+// Headers of W3C trace context
+const (
+	HeaderTraceparent = "traceparent"
+	HeaderTracestate  = "tracestate"
+)
+
+// FlagSampled - trace flag which means the trace is recorded
+const FlagSampled byte = 0x01
+
+// TraceID - identifier of the trace
+type TraceID [16]byte
+
+// IsValid returns false for all zeros ID.
+func (t TraceID) IsValid() bool {
+	return t != TraceID{}
+}
+
+func (t TraceID) String() string {
+	return hex.EncodeToString(t[:])
+}
+
+// SpanID - identifier of the span
+type SpanID [8]byte
+
+// IsValid returns false for all zeros ID.
+func (s SpanID) IsValid() bool {
+	return s != SpanID{}
+}
+
+func (s SpanID) String() string {
+	return hex.EncodeToString(s[:])
+}
+
+// SpanContext - part of the span which is propagated across services
+type SpanContext struct {
+	TraceID    TraceID
+	SpanID     SpanID
+	Flags      byte
+	TraceState string
+
+	// Remote is true for span context received from another service.
+	Remote bool
+}
+
+// IsValid returns true if trace and span IDs are valid.
+func (sc SpanContext) IsValid() bool {
+	return sc.TraceID.IsValid() && sc.SpanID.IsValid()
+}
+
+// IsSampled returns true if the trace is recorded.
+func (sc SpanContext) IsSampled() bool {
+	return sc.Flags&FlagSampled != 0
+}
+
+// Traceparent returns value of traceparent header.
+func (sc SpanContext) Traceparent() string {
+	return fmt.Sprintf("00-%s-%s-%02x", sc.TraceID, sc.SpanID, sc.Flags)
+}
+
+// ParseTraceparent parses value of traceparent header.
+func ParseTraceparent(value string) (SpanContext, error) {
+	var sc SpanContext
+
+	// version "-" trace-id "-" parent-id "-" trace-flags, future versions may append fields
+	if len(value) < 55 || value[2] != '-' || value[35] != '-' || value[52] != '-' {
+		return sc, errors.New("tracing: invalid traceparent format")
+	}
+
+	version, err := decodeHex(value[0:2])
+	if err != nil || version[0] == 0xff {
+		return sc, errors.New("tracing: invalid traceparent version")
+	}
+	if version[0] == 0 && len(value) != 55 {
+		return sc, errors.New("tracing: invalid traceparent length")
+	}
+	if len(value) > 55 && value[55] != '-' {
+		return sc, errors.New("tracing: invalid traceparent format")
+	}
+
+	traceID, err := decodeHex(value[3:35])
+	if err != nil {
+		return sc, errors.New("tracing: invalid trace ID")
+	}
+	spanID, err := decodeHex(value[36:52])
+	if err != nil {
+		return sc, errors.New("tracing: invalid parent ID")
+	}
+	flags, err := decodeHex(value[53:55])
+	if err != nil {
+		return sc, errors.New("tracing: invalid trace flags")
+	}
+
+	copy(sc.TraceID[:], traceID)
+	copy(sc.SpanID[:], spanID)
+	sc.Flags = flags[0]
+	sc.Remote = true
+
+	if !sc.IsValid() {
+		return SpanContext{}, errors.New("tracing: zero trace or parent ID")
+	}
+	return sc, nil
+}
+
+// Extract returns span context of the request headers.
+func Extract(header nethttp.Header) (SpanContext, error) {
+	sc, err := ParseTraceparent(header.Get(HeaderTraceparent))
+	if err != nil {
+		return sc, err
+	}
+	sc.TraceState = header.Get(HeaderTracestate)
+	return sc, nil
+}
+
+// Inject sets trace context headers of the outgoing request.
+func Inject(sc SpanContext, header nethttp.Header) {
+	if !sc.IsValid() {
+		return
+	}
+
+	header.Set(HeaderTraceparent, sc.Traceparent())
+	if sc.TraceState != "" {
+		header.Set(HeaderTracestate, sc.TraceState)
+	}
+}
+
+// decodeHex decodes lowercase hex string, uppercase is forbidden by the specification.
+func decodeHex(s string) ([]byte, error) {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= 'A' && s[i] <= 'F' {
+			return nil, errors.New("uppercase hex")
+		}
+	}
+	return hex.DecodeString(s)
+}
+
+func newTraceID() TraceID {
+	var id TraceID
+	randomBytes(id[:])
+	return id
+}
+
+func newSpanID() SpanID {
+	var id SpanID
+	randomBytes(id[:])
+	return id
+}
+
+func randomBytes(b []byte) {
+	_, err := rand.Read(b)
+	if err != nil {
+		panic(fmt.Sprintf("tracing: can't read random bytes: %v", err))
+	}
+}
@@ -0,0 +1,107 @@
+package tracing_test
+
+import (
+	"github.com/ok93-01-18/go-ms-lib/tracing"
+	nethttp "net/http"
+	"testing"
+)
+
+const (
+	traceID     = "4bf92f3577b34da6a3ce929d0e0e4736"
+	spanID      = "00f067aa0ba902b7"
+	traceparent = "00-" + traceID + "-" + spanID + "-01"
+)
+
+func TestParseTraceparent(t *testing.T) {
+	for _, test := range []struct {
+		name    string
+		value   string
+		wantErr bool
+	}{
+		{name: "valid", value: traceparent},
+		{name: "not sampled", value: "00-" + traceID + "-" + spanID + "-00"},
+		{name: "future version with fields", value: "01-" + traceID + "-" + spanID + "-01-extra"},
+		{name: "empty", value: "", wantErr: true},
+		{name: "forbidden version", value: "ff-" + traceID + "-" + spanID + "-01", wantErr: true},
+		{name: "invalid version", value: "0x-" + traceID + "-" + spanID + "-01", wantErr: true},
+		{name: "version 00 with fields", value: traceparent + "-extra", wantErr: true},
+		{name: "future version without separator", value: "01-" + traceID + "-" + spanID + "-01extra", wantErr: true},
+		{name: "invalid separator", value: "00_" + traceID + "-" + spanID + "-01", wantErr: true},
+		{name: "uppercase trace ID", value: "00-4BF92F3577B34DA6A3CE929D0E0E4736-" + spanID + "-01", wantErr: true},
+		{name: "invalid trace ID", value: "00-4bf92f3577b34da6a3ce929d0e0e473z-" + spanID + "-01", wantErr: true},
+		{name: "zero trace ID", value: "00-00000000000000000000000000000000-" + spanID + "-01", wantErr: true},
+		{name: "invalid parent ID", value: "00-" + traceID + "-00f067aa0ba902bz-01", wantErr: true},
+		{name: "zero parent ID", value: "00-" + traceID + "-0000000000000000-01", wantErr: true},
+		{name: "invalid flags", value: "00-" + traceID + "-" + spanID + "-0g", wantErr: true},
+		{name: "uppercase flags", value: "00-" + traceID + "-" + spanID + "-0A", wantErr: true},
+	} {
+		sc, err := tracing.ParseTraceparent(test.value)
+		if test.wantErr {
+			if err == nil {
+				t.Errorf("%s: ParseTraceparent(%q) returned no error", test.name, test.value)
+			}
+			if sc.IsValid() {
+				t.Errorf("%s: ParseTraceparent(%q) = %+v, want zero span context", test.name, test.value, sc)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("%s: ParseTraceparent(%q) = %v", test.name, test.value, err)
+			continue
+		}
+		if sc.TraceID.String() != traceID || sc.SpanID.String() != spanID || !sc.Remote {
+			t.Errorf("%s: ParseTraceparent(%q) = %+v", test.name, test.value, sc)
+		}
+	}
+}
+
+func TestSpanContext_Traceparent(t *testing.T) {
+	sc, err := tracing.ParseTraceparent(traceparent)
+	if err != nil {
+		t.Fatalf("ParseTraceparent() = %v", err)
+	}
+	if got := sc.Traceparent(); got != traceparent {
+		t.Errorf("Traceparent() = %q, want %q", got, traceparent)
+	}
+	if !sc.IsSampled() {
+		t.Error("IsSampled() = false, want true")
+	}
+
+	sc.Flags = 0
+	if got, want := sc.Traceparent(), "00-"+traceID+"-"+spanID+"-00"; got != want {
+		t.Errorf("Traceparent() = %q, want %q", got, want)
+	}
+	if sc.IsSampled() {
+		t.Error("IsSampled() = true, want false")
+	}
+}
+
+func TestInjectExtract(t *testing.T) {
+	sc, err := tracing.ParseTraceparent(traceparent)
+	if err != nil {
+		t.Fatalf("ParseTraceparent() = %v", err)
+	}
+	sc.TraceState = "vendor=value"
+
+	header := nethttp.Header{}
+	tracing.Inject(sc, header)
+	if got := header.Get(tracing.HeaderTraceparent); got != traceparent {
+		t.Errorf("%s = %q, want %q", tracing.HeaderTraceparent, got, traceparent)
+	}
+
+	extracted, err := tracing.Extract(header)
+	if err != nil {
+		t.Fatalf("Extract() = %v", err)
+	}
+	if extracted != sc {
+		t.Errorf("Extract() = %+v, want %+v", extracted, sc)
+	}
+
+	// invalid span context isn't propagated
+	header = nethttp.Header{}
+	tracing.Inject(tracing.SpanContext{}, header)
+	if len(header) != 0 {
+		t.Errorf("Inject() of invalid span context = %v", header)
+	}
+}
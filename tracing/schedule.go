@@ -0,0 +1,49 @@
+package tracing
+
+import (
+	"context"
+	"github.com/ok93-01-18/go-ms-lib/schedule"
+)
+
+// ContextTask - task which receives context with the span of the run, so it can start child spans
+type ContextTask interface {
+	schedule.TaskInterface
+
+	DoContext(ctx context.Context) error
+}
+
+type task struct {
+	tracer *Tracer
+	name   string
+	task   schedule.TaskInterface
+}
+
+func (t *task) Do() error {
+	ctx, span := t.tracer.Start(context.Background(), t.name, SpanKindInternal)
+	span.SetAttribute("job.name", t.name)
+
+	var err error
+	if contextTask, ok := t.task.(ContextTask); ok {
+		err = contextTask.DoContext(ctx)
+	} else {
+		err = t.task.Do()
+	}
+
+	span.RecordError(err)
+	span.End()
+
+	return err
+}
+
+// NewTask - return task which runs the task within root span named by the name
+func NewTask(tracer *Tracer, name string, t schedule.TaskInterface) schedule.TaskInterface {
+	return &task{tracer: tracer, name: name, task: t}
+}
+
+// TraceOperations wraps tasks of the operations by NewTask named by the operation name,
+// it must be called before CronManager.Init.
+func TraceOperations(tracer *Tracer, operations []schedule.Operation) {
+	for i := range operations {
+		operations[i].Task = NewTask(tracer, operations[i].Name, operations[i].Task)
+	}
+}
@@ -0,0 +1,150 @@
+package tracing
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// SpanKind - role of the span in the trace
+type SpanKind int
+
+// Span kinds, values match OTLP
+const (
+	SpanKindInternal SpanKind = 1
+	SpanKindServer   SpanKind = 2
+	SpanKindClient   SpanKind = 3
+	SpanKindProducer SpanKind = 4
+	SpanKindConsumer SpanKind = 5
+)
+
+// StatusCode - status of the span, values match OTLP
+type StatusCode int
+
+const (
+	StatusUnset StatusCode = 0
+	StatusOK    StatusCode = 1
+	StatusError StatusCode = 2
+)
+
+// SpanData - snapshot of the ended span passed to Exporter
+type SpanData struct {
+	Name          string
+	Kind          SpanKind
+	Context       SpanContext
+	Parent        SpanID
+	Start         time.Time
+	End           time.Time
+	Attributes    map[string]interface{}
+	Status        StatusCode
+	StatusMessage string
+}
+
+// Span - operation of the trace
+type Span struct {
+	mu     sync.Mutex
+	tracer *Tracer
+	data   SpanData
+	ended  bool
+}
+
+// Context returns span context which is propagated to children and other services.
+func (s *Span) Context() SpanContext {
+	return s.data.Context
+}
+
+// SetName changes name of the span, calls after End are ignored.
+func (s *Span) SetName(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.ended {
+		return
+	}
+	s.data.Name = name
+}
+
+// SetAttribute sets attribute of the span, the value should be string, bool, integer or float.
+// Calls after End are ignored.
+func (s *Span) SetAttribute(key string, value interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.ended {
+		return
+	}
+	if s.data.Attributes == nil {
+		s.data.Attributes = make(map[string]interface{})
+	}
+	s.data.Attributes[key] = value
+}
+
+// SetStatus sets status of the span, the message is used for errors only.
+// Calls after End are ignored.
+func (s *Span) SetStatus(code StatusCode, message string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.ended {
+		return
+	}
+	s.data.Status = code
+	if code == StatusError {
+		s.data.StatusMessage = message
+	}
+}
+
+// RecordError sets error status of the span if err is not nil.
+func (s *Span) RecordError(err error) {
+	if err != nil {
+		s.SetStatus(StatusError, err.Error())
+	}
+}
+
+// End ends the span and passes it to the tracer exporter if the trace is sampled.
+// Calls after the first one are ignored.
+func (s *Span) End() {
+	s.mu.Lock()
+	if s.ended {
+		s.mu.Unlock()
+		return
+	}
+	s.ended = true
+	s.data.End = time.Now()
+	data := s.data
+	s.mu.Unlock()
+
+	// attributes are copied, so the exported snapshot doesn't share the map with the span
+	if data.Attributes != nil {
+		attributes := make(map[string]interface{}, len(data.Attributes))
+		for key, value := range data.Attributes {
+			attributes[key] = value
+		}
+		data.Attributes = attributes
+	}
+
+	if s.tracer != nil && data.Context.IsSampled() {
+		s.tracer.enqueue(data)
+	}
+}
+
+type spanKey struct{}
+
+// ContextWithSpan returns the context with the span, spans started with it become children of the span.
+func ContextWithSpan(ctx context.Context, span *Span) context.Context {
+	return context.WithValue(ctx, spanKey{}, span)
+}
+
+// SpanFromContext returns span of the context, nil is returned if there is no span.
+func SpanFromContext(ctx context.Context) *Span {
+	span, _ := ctx.Value(spanKey{}).(*Span)
+	return span
+}
+
+type remoteKey struct{}
+
+// ContextWithRemoteParent returns the context with span context received from another service,
+// span started with it becomes child of the remote span.
+func ContextWithRemoteParent(ctx context.Context, sc SpanContext) context.Context {
+	return context.WithValue(ctx, remoteKey{}, sc)
+}
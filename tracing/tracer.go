@@ -0,0 +1,212 @@
+package tracing
+
+import (
+	"context"
+	"github.com/ok93-01-18/go-ms-lib/log"
+	"sync"
+	"time"
+)
+
+// Defaults used for zero values of Config
+const (
+	DefaultBatchSize     = 512
+	DefaultMaxQueueSize  = 2048
+	DefaultFlushInterval = 5 * time.Second
+)
+
+type Config struct {
+	// Exporter receives ended spans of sampled traces.
+	Exporter Exporter
+
+	// BatchSize - max number of spans passed to the exporter at once, DefaultBatchSize is used when zero.
+	BatchSize int
+
+	// MaxQueueSize - spans ended while the queue is full are dropped, DefaultMaxQueueSize is used when zero.
+	MaxQueueSize int
+
+	// FlushInterval - interval of exporting queued spans, DefaultFlushInterval is used when zero.
+	FlushInterval time.Duration
+
+	// Logger receives export errors to the app channel, they are not logged when nil.
+	Logger log.Logger
+}
+
+// Tracer - starts spans and exports them in batches
+type Tracer struct {
+	conf  Config
+	mu    sync.Mutex
+	queue []SpanData
+
+	// exportMu serializes exports of the background loop and Flush.
+	exportMu sync.Mutex
+	full     chan struct{}
+	stop     chan struct{}
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+// Start starts span with the parent of the context: local span or remote span context.
+// New trace is started if the context has no parent. The returned context holds the started span.
+func (t *Tracer) Start(ctx context.Context, name string, kind SpanKind) (context.Context, *Span) {
+	var parent SpanContext
+	if span := SpanFromContext(ctx); span != nil {
+		parent = span.Context()
+	} else if sc, ok := ctx.Value(remoteKey{}).(SpanContext); ok {
+		parent = sc
+	}
+
+	span := t.StartSpan(name, kind, parent)
+	return ContextWithSpan(ctx, span), span
+}
+
+// StartSpan starts child span of the parent, new trace is started if the parent is not valid.
+func (t *Tracer) StartSpan(name string, kind SpanKind, parent SpanContext) *Span {
+	sc := SpanContext{SpanID: newSpanID()}
+	var parentID SpanID
+	if parent.IsValid() {
+		sc.TraceID = parent.TraceID
+		sc.Flags = parent.Flags
+		sc.TraceState = parent.TraceState
+		parentID = parent.SpanID
+	} else {
+		sc.TraceID = newTraceID()
+		sc.Flags = FlagSampled
+	}
+
+	return &Span{
+		tracer: t,
+		data: SpanData{
+			Name:    name,
+			Kind:    kind,
+			Context: sc,
+			Parent:  parentID,
+			Start:   time.Now(),
+		},
+	}
+}
+
+// Flush exports all queued spans. Failed export of the batch is retried once,
+// then the batch is dropped and the error is returned, so spans of broken exporters don't pile up.
+func (t *Tracer) Flush(ctx context.Context) error {
+	for {
+		batch := t.dequeue()
+		if len(batch) == 0 {
+			return nil
+		}
+
+		err := t.export(ctx, batch)
+		if err != nil && ctx.Err() == nil {
+			err = t.export(ctx, batch)
+		}
+		if err != nil {
+			if t.conf.Logger != nil {
+				t.conf.Logger.Errorf(log.TypeApp, "tracing: export of %d spans failed, they are dropped: %v", len(batch), err)
+			}
+			return err
+		}
+	}
+}
+
+// Shutdown stops the background export, flushes queued spans and shuts down the exporter.
+func (t *Tracer) Shutdown(ctx context.Context) error {
+	t.stopOnce.Do(func() {
+		close(t.stop)
+	})
+
+	select {
+	case <-t.done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	err := t.Flush(ctx)
+	shutdownErr := t.conf.Exporter.Shutdown(ctx)
+	if err != nil {
+		return err
+	}
+	return shutdownErr
+}
+
+func (t *Tracer) enqueue(data SpanData) {
+	t.mu.Lock()
+	if len(t.queue) >= t.conf.MaxQueueSize {
+		t.mu.Unlock()
+		return
+	}
+	t.queue = append(t.queue, data)
+	full := len(t.queue) >= t.conf.BatchSize
+	t.mu.Unlock()
+
+	if full {
+		select {
+		case t.full <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func (t *Tracer) dequeue() []SpanData {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	n := len(t.queue)
+	if n > t.conf.BatchSize {
+		n = t.conf.BatchSize
+	}
+
+	batch := t.queue[:n:n]
+	t.queue = t.queue[n:]
+	return batch
+}
+
+func (t *Tracer) export(ctx context.Context, batch []SpanData) error {
+	t.exportMu.Lock()
+	defer t.exportMu.Unlock()
+
+	return t.conf.Exporter.Export(ctx, batch)
+}
+
+// loop exports queued spans periodically or when the batch is full.
+func (t *Tracer) loop() {
+	defer close(t.done)
+
+	ticker := time.NewTicker(t.conf.FlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+		case <-t.full:
+		case <-t.stop:
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), t.conf.FlushInterval)
+		_ = t.Flush(ctx)
+		cancel()
+	}
+}
+
+// NewTracer - return Tracer which exports spans in background until Shutdown
+func NewTracer(conf *Config) *Tracer {
+	c := *conf
+	if c.BatchSize == 0 {
+		c.BatchSize = DefaultBatchSize
+	}
+	if c.MaxQueueSize == 0 {
+		c.MaxQueueSize = DefaultMaxQueueSize
+	}
+	if c.FlushInterval == 0 {
+		c.FlushInterval = DefaultFlushInterval
+	}
+
+	t := &Tracer{
+		conf: c,
+		full: make(chan struct{}, 1),
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+	go t.loop()
+
+	return t
+}